@@ -0,0 +1,329 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds all settings of the bot, loaded once at startup by Load
+type Config struct {
+	Telegram TelegramConfig `yaml:"telegram"`
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	AI       AIConfig       `yaml:"ai"`
+	Workers  WorkersConfig  `yaml:"workers"`
+	Import   ImportConfig   `yaml:"import"`
+
+	// Timezone is used for schedules and "today" boundaries
+	Timezone string `yaml:"timezone"`
+	// Location is the loaded Timezone
+	Location *time.Location `yaml:"-"`
+}
+
+type TelegramConfig struct {
+	BotToken string `yaml:"bot_token"`
+	// UserID is the only Telegram user allowed to use the bot
+	UserID int64 `yaml:"user_id"`
+	// Mode is "webhook" or "polling"
+	Mode          string `yaml:"mode"`
+	WebhookSecret string `yaml:"webhook_secret"`
+}
+
+type ServerConfig struct {
+	Port            int           `yaml:"port"`
+	PublicURL       string        `yaml:"public_url"`
+	AdminToken      string        `yaml:"admin_token"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// InstanceID identifies this process when taking job locks
+	InstanceID string `yaml:"instance_id"`
+}
+
+type DatabaseConfig struct {
+	URL string `yaml:"url"`
+	// MigrateOnStart applies pending migrations when the server starts
+	MigrateOnStart bool `yaml:"migrate_on_start"`
+}
+
+type AIConfig struct {
+	APIKey  string        `yaml:"api_key"`
+	Model   string        `yaml:"model"`
+	BaseURL string        `yaml:"base_url"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type WorkersConfig struct {
+	Count         int           `yaml:"count"`
+	QueueSize     int           `yaml:"queue_size"`
+	UpdateTimeout time.Duration `yaml:"update_timeout"`
+}
+
+type ImportConfig struct {
+	// MaxFileSize is the largest bank statement accepted, in bytes
+	MaxFileSize int64 `yaml:"max_file_size"`
+}
+
+// ValidationError lists every invalid or missing setting
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// webhookSecretPattern is the character set Telegram accepts for secret_token
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// Default returns the configuration used for settings that are not provided
+func Default() *Config {
+	return &Config{
+		Telegram: TelegramConfig{
+			Mode: "webhook",
+		},
+		Server: ServerConfig{
+			Port:            8080,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			MigrateOnStart: true,
+		},
+		AI: AIConfig{
+			Model:   "openai/gpt-3.5-turbo",
+			BaseURL: "https://openrouter.ai/api/v1",
+			Timeout: 30 * time.Second,
+		},
+		Workers: WorkersConfig{
+			Count:         4,
+			QueueSize:     100,
+			UpdateTimeout: 60 * time.Second,
+		},
+		Import: ImportConfig{
+			MaxFileSize: 5 << 20,
+		},
+		Timezone: "Asia/Jakarta",
+	}
+}
+
+// Load builds the configuration from the defaults, the YAML file named by CONFIG_FILE
+// (config.yaml when present) and environment variables, in increasing priority
+func Load() (*Config, error) {
+	cfg, problems, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.validate(&problems)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return cfg, nil
+}
+
+// LoadDatabase loads the configuration like Load but only requires the database settings,
+// for commands such as migrate that do not run the bot
+func LoadDatabase() (DatabaseConfig, error) {
+	cfg, problems, err := read()
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+
+	if cfg.Database.URL == "" {
+		problems = append(problems, "DATABASE_URL is not set")
+	}
+	if len(problems) > 0 {
+		return DatabaseConfig{}, &ValidationError{Problems: problems}
+	}
+
+	return cfg.Database, nil
+}
+
+// read applies the config file and the environment to the defaults without validating the result
+func read() (*Config, []string, error) {
+	cfg := Default()
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		path = "config.yaml"
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			path = ""
+		}
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var problems []string
+	cfg.loadEnv(&problems)
+	return cfg, problems, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv overrides settings with the environment variables that are set
+func (c *Config) loadEnv(problems *[]string) {
+	envString("TELEGRAM_BOT_TOKEN", &c.Telegram.BotToken)
+	envInt64("TELEGRAM_USER_ID", &c.Telegram.UserID, problems)
+	envString("BOT_MODE", &c.Telegram.Mode)
+	envString("WEBHOOK_SECRET", &c.Telegram.WebhookSecret)
+
+	envInt("PORT", &c.Server.Port, problems)
+	// Render provides its own URL when PUBLIC_URL is not set
+	envString("RENDER_EXTERNAL_URL", &c.Server.PublicURL)
+	envString("PUBLIC_URL", &c.Server.PublicURL)
+	envString("ADMIN_TOKEN", &c.Server.AdminToken)
+	envDuration("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout, problems)
+	envString("RENDER_INSTANCE_ID", &c.Server.InstanceID)
+	envString("INSTANCE_ID", &c.Server.InstanceID)
+
+	envString("DATABASE_URL", &c.Database.URL)
+	envBool("MIGRATE_ON_START", &c.Database.MigrateOnStart, problems)
+
+	envString("OPENROUTER_API_KEY", &c.AI.APIKey)
+	envString("AI_MODEL", &c.AI.Model)
+	envString("AI_BASE_URL", &c.AI.BaseURL)
+	envDuration("AI_TIMEOUT", &c.AI.Timeout, problems)
+
+	envInt("WORKER_COUNT", &c.Workers.Count, problems)
+	envInt("WORKER_QUEUE_SIZE", &c.Workers.QueueSize, problems)
+	envDuration("UPDATE_TIMEOUT", &c.Workers.UpdateTimeout, problems)
+
+	envInt64("IMPORT_MAX_FILE_SIZE", &c.Import.MaxFileSize, problems)
+
+	envString("TIMEZONE", &c.Timezone)
+}
+
+// validate checks required settings and ranges, loading the timezone on the way
+func (c *Config) validate(problems *[]string) {
+	if c.Telegram.BotToken == "" {
+		*problems = append(*problems, "TELEGRAM_BOT_TOKEN is not set")
+	}
+	if c.Telegram.UserID == 0 {
+		*problems = append(*problems, "TELEGRAM_USER_ID is not set")
+	}
+	if c.Telegram.Mode != "webhook" && c.Telegram.Mode != "polling" {
+		*problems = append(*problems, "BOT_MODE must be either webhook or polling")
+	}
+	if c.Telegram.WebhookSecret != "" && !webhookSecretPattern.MatchString(c.Telegram.WebhookSecret) {
+		*problems = append(*problems, "WEBHOOK_SECRET may only contain A-Z, a-z, 0-9, _ and - (1-256 characters)")
+	}
+	if c.Database.URL == "" {
+		*problems = append(*problems, "DATABASE_URL is not set")
+	}
+	if c.AI.Model == "" {
+		*problems = append(*problems, "AI_MODEL must not be empty")
+	}
+	if c.AI.BaseURL == "" {
+		*problems = append(*problems, "AI_BASE_URL must not be empty")
+	}
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		*problems = append(*problems, "PORT must be between 1 and 65535")
+	}
+	if c.Workers.Count < 1 {
+		*problems = append(*problems, "WORKER_COUNT must be at least 1")
+	}
+	if c.Workers.QueueSize < 0 {
+		*problems = append(*problems, "WORKER_QUEUE_SIZE must not be negative")
+	}
+	if c.Import.MaxFileSize <= 0 {
+		*problems = append(*problems, "IMPORT_MAX_FILE_SIZE must be positive")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		*problems = append(*problems, "SHUTDOWN_TIMEOUT must be positive")
+	}
+	if c.AI.Timeout <= 0 {
+		*problems = append(*problems, "AI_TIMEOUT must be positive")
+	}
+	if c.Workers.UpdateTimeout <= 0 {
+		*problems = append(*problems, "UPDATE_TIMEOUT must be positive")
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		*problems = append(*problems, fmt.Sprintf("TIMEZONE %q is unknown", c.Timezone))
+	} else {
+		c.Location = loc
+	}
+
+	if c.Server.InstanceID == "" {
+		hostname, _ := os.Hostname()
+		c.Server.InstanceID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+}
+
+func envString(key string, target *string) {
+	if value := os.Getenv(key); value != "" {
+		*target = value
+	}
+}
+
+func envBool(key string, target *bool, problems *[]string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		*problems = append(*problems, key+" must be true or false")
+		return
+	}
+	*target = b
+}
+
+func envInt(key string, target *int, problems *[]string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		*problems = append(*problems, key+" must be a number")
+		return
+	}
+	*target = n
+}
+
+func envInt64(key string, target *int64, problems *[]string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		*problems = append(*problems, key+" must be a valid integer")
+		return
+	}
+	*target = n
+}
+
+func envDuration(key string, target *time.Duration, problems *[]string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		*problems = append(*problems, key+` must be a duration such as "30s"`)
+		return
+	}
+	*target = d
+}
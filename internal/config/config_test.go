@@ -0,0 +1,197 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+	// The binary embeds the zone database, so the test does not rely on the host's either
+	_ "time/tzdata"
+)
+
+// configEnv lists every variable Load reads
+var configEnv = []string{
+	"CONFIG_FILE", "TELEGRAM_BOT_TOKEN", "TELEGRAM_USER_ID", "BOT_MODE", "WEBHOOK_SECRET",
+	"PORT", "RENDER_EXTERNAL_URL", "PUBLIC_URL", "ADMIN_TOKEN", "SHUTDOWN_TIMEOUT",
+	"RENDER_INSTANCE_ID", "INSTANCE_ID", "DATABASE_URL", "MIGRATE_ON_START",
+	"OPENROUTER_API_KEY", "AI_MODEL", "AI_BASE_URL", "AI_TIMEOUT",
+	"WORKER_COUNT", "WORKER_QUEUE_SIZE", "UPDATE_TIMEOUT", "IMPORT_MAX_FILE_SIZE", "TIMEZONE",
+}
+
+// setEnv clears the configuration variables, then sets the required ones and env on top
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	for _, key := range configEnv {
+		t.Setenv(key, "")
+	}
+	// An empty config file, so that a config.yaml in the working directory is not read
+	empty := filepath.Join(t.TempDir(), "empty.yaml")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", empty)
+	required := map[string]string{
+		"TELEGRAM_BOT_TOKEN": "123:abc",
+		"TELEGRAM_USER_ID":   "42",
+		"DATABASE_URL":       "sqlite::memory:",
+	}
+	for key, value := range required {
+		t.Setenv(key, value)
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	setEnv(t, nil)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Telegram.UserID != 42 || cfg.Telegram.Mode != "webhook" || cfg.Server.Port != 8080 {
+		t.Errorf("user, mode, port = %d, %q, %d, want 42, webhook, 8080", cfg.Telegram.UserID, cfg.Telegram.Mode, cfg.Server.Port)
+	}
+	if cfg.Workers.Count != 4 || cfg.Workers.QueueSize != 100 || cfg.Import.MaxFileSize != 5<<20 {
+		t.Errorf("workers, queue, max file size = %d, %d, %d", cfg.Workers.Count, cfg.Workers.QueueSize, cfg.Import.MaxFileSize)
+	}
+	if cfg.Location == nil || cfg.Location.String() != "Asia/Jakarta" {
+		t.Errorf("location = %v, want Asia/Jakarta", cfg.Location)
+	}
+	if cfg.AI.APIKey != "" {
+		t.Errorf("AI key = %q, want none", cfg.AI.APIKey)
+	}
+	if cfg.Server.InstanceID == "" {
+		t.Error("no instance ID was derived")
+	}
+}
+
+func TestLoadEnvironment(t *testing.T) {
+	setEnv(t, map[string]string{
+		"BOT_MODE":             "polling",
+		"PORT":                 "3000",
+		"RENDER_EXTERNAL_URL":  "https://render.example",
+		"PUBLIC_URL":           "https://bot.example",
+		"MIGRATE_ON_START":     "false",
+		"OPENROUTER_API_KEY":   "sk-test",
+		"AI_TIMEOUT":           "5s",
+		"WORKER_COUNT":         "8",
+		"WORKER_QUEUE_SIZE":    "0",
+		"IMPORT_MAX_FILE_SIZE": "1024",
+		"TIMEZONE":             "UTC",
+		"INSTANCE_ID":          "web-1",
+	})
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Telegram.Mode != "polling" || cfg.Server.Port != 3000 || cfg.Server.InstanceID != "web-1" {
+		t.Errorf("mode, port, instance = %q, %d, %q", cfg.Telegram.Mode, cfg.Server.Port, cfg.Server.InstanceID)
+	}
+	// PUBLIC_URL wins over the URL Render provides
+	if cfg.Server.PublicURL != "https://bot.example" {
+		t.Errorf("public URL = %q, want https://bot.example", cfg.Server.PublicURL)
+	}
+	if cfg.Database.MigrateOnStart || cfg.AI.APIKey != "sk-test" || cfg.AI.Timeout != 5*time.Second {
+		t.Errorf("migrate, AI key, AI timeout = %v, %q, %v", cfg.Database.MigrateOnStart, cfg.AI.APIKey, cfg.AI.Timeout)
+	}
+	if cfg.Workers.Count != 8 || cfg.Workers.QueueSize != 0 || cfg.Import.MaxFileSize != 1024 {
+		t.Errorf("workers, queue, max file size = %d, %d, %d", cfg.Workers.Count, cfg.Workers.QueueSize, cfg.Import.MaxFileSize)
+	}
+	if cfg.Location != time.UTC {
+		t.Errorf("location = %v, want UTC", cfg.Location)
+	}
+}
+
+func TestLoadRejectsInvalidSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string
+	}{
+		{"missing required", map[string]string{"TELEGRAM_BOT_TOKEN": "", "TELEGRAM_USER_ID": "", "DATABASE_URL": ""},
+			[]string{"TELEGRAM_BOT_TOKEN is not set", "TELEGRAM_USER_ID is not set", "DATABASE_URL is not set"}},
+		{"user ID not a number", map[string]string{"TELEGRAM_USER_ID": "me"},
+			[]string{"TELEGRAM_USER_ID must be a valid integer", "TELEGRAM_USER_ID is not set"}},
+		{"unknown mode", map[string]string{"BOT_MODE": "push"}, []string{"BOT_MODE must be either webhook or polling"}},
+		{"webhook secret", map[string]string{"WEBHOOK_SECRET": "has spaces"},
+			[]string{"WEBHOOK_SECRET may only contain A-Z, a-z, 0-9, _ and - (1-256 characters)"}},
+		{"unknown timezone", map[string]string{"TIMEZONE": "Asia/Atlantis"}, []string{`TIMEZONE "Asia/Atlantis" is unknown`}},
+		{"port out of range", map[string]string{"PORT": "70000"}, []string{"PORT must be between 1 and 65535"}},
+		{"port not a number", map[string]string{"PORT": "http"}, []string{"PORT must be a number"}},
+		{"no workers", map[string]string{"WORKER_COUNT": "0"}, []string{"WORKER_COUNT must be at least 1"}},
+		{"negative queue", map[string]string{"WORKER_QUEUE_SIZE": "-1"}, []string{"WORKER_QUEUE_SIZE must not be negative"}},
+		{"file size", map[string]string{"IMPORT_MAX_FILE_SIZE": "0"}, []string{"IMPORT_MAX_FILE_SIZE must be positive"}},
+		{"durations", map[string]string{"SHUTDOWN_TIMEOUT": "0s", "AI_TIMEOUT": "-1s", "UPDATE_TIMEOUT": "soon"},
+			[]string{"SHUTDOWN_TIMEOUT must be positive", "AI_TIMEOUT must be positive", `UPDATE_TIMEOUT must be a duration such as "30s"`}},
+		{"boolean", map[string]string{"MIGRATE_ON_START": "sometimes"}, []string{"MIGRATE_ON_START must be true or false"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+
+			_, err := Load()
+			var validation *ValidationError
+			if !errors.As(err, &validation) {
+				t.Fatalf("Load: err = %v, want a ValidationError", err)
+			}
+			for _, want := range tt.want {
+				if !slices.Contains(validation.Problems, want) {
+					t.Errorf("problems %q do not include %q", validation.Problems, want)
+				}
+			}
+			if len(validation.Problems) != len(tt.want) {
+				t.Errorf("problems = %q, want %q", validation.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	setEnv(t, map[string]string{"PORT": "9000"})
+
+	path := filepath.Join(t.TempDir(), "bot.yaml")
+	content := "server:\n  port: 7000\n  public_url: https://file.example\nworkers:\n  count: 2\ntimezone: UTC\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	// The environment wins over the file, which wins over the defaults
+	if cfg.Server.Port != 9000 || cfg.Server.PublicURL != "https://file.example" || cfg.Workers.Count != 2 {
+		t.Errorf("port, public URL, workers = %d, %q, %d, want 9000, https://file.example, 2", cfg.Server.Port, cfg.Server.PublicURL, cfg.Workers.Count)
+	}
+
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, err := Load(); err == nil {
+		t.Error("Load with a missing CONFIG_FILE succeeded")
+	}
+}
+
+func TestLoadDatabase(t *testing.T) {
+	setEnv(t, map[string]string{"TELEGRAM_BOT_TOKEN": "", "TELEGRAM_USER_ID": ""})
+
+	db, err := LoadDatabase()
+	if err != nil {
+		t.Fatalf("LoadDatabase without the bot settings: %v", err)
+	}
+	if db.URL != "sqlite::memory:" || !db.MigrateOnStart {
+		t.Errorf("database = %+v", db)
+	}
+
+	t.Setenv("DATABASE_URL", "")
+	var validation *ValidationError
+	if _, err := LoadDatabase(); !errors.As(err, &validation) {
+		t.Errorf("LoadDatabase without DATABASE_URL: err = %v, want a ValidationError", err)
+	}
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+func (s *Store) CreateAPIToken(token *models.APIToken) error {
+	result := s.db.Create(token)
+	return result.Error
+}
+
+func (s *Store) GetAPITokenByHash(tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	result := s.db.Where("token_hash = ?", tokenHash).First(&token)
+	if result.Error != nil {
+		return nil, notFound(result.Error)
+	}
+	return &token, nil
+}
+
+func (s *Store) GetAPITokensByUserID(userID uint) ([]models.APIToken, error) {
+	var tokens []models.APIToken
+	result := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens)
+	return tokens, result.Error
+}
+
+// TouchAPIToken records when a token was last used
+func (s *Store) TouchAPIToken(tokenID uint, usedAt time.Time) error {
+	result := s.db.Model(&models.APIToken{}).Where("id = ?", tokenID).Update("last_used_at", usedAt)
+	return result.Error
+}
+
+// DeleteAPITokens revokes every token of a user
+func (s *Store) DeleteAPITokens(userID uint) (int64, error) {
+	result := s.db.Where("user_id = ?", userID).Delete(&models.APIToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func (s *Store) CreateExpenseEvents(events []models.ExpenseEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	result := s.db.CreateInBatches(events, 100)
+	return result.Error
+}
+
+// GetExpenseEvents returns the events of one expense, oldest first
+func (s *Store) GetExpenseEvents(userID uint, expenseID uint) ([]models.ExpenseEvent, error) {
+	var events []models.ExpenseEvent
+	result := s.db.Where("user_id = ? AND expense_id = ?", userID, expenseID).Order("id").Find(&events)
+	return events, result.Error
+}
+
+// GetLastExpenseEvents returns the events of the user's most recent batch that has not been undone
+func (s *Store) GetLastExpenseEvents(userID uint) ([]models.ExpenseEvent, error) {
+	var last models.ExpenseEvent
+	result := s.db.Where("user_id = ? AND undone_at IS NULL", userID).Order("id DESC").First(&last)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var events []models.ExpenseEvent
+	result = s.db.Where("user_id = ? AND batch = ? AND undone_at IS NULL", userID, last.Batch).Order("id").Find(&events)
+	return events, result.Error
+}
+
+// MarkExpenseEventsUndone records that the events of a batch were undone
+func (s *Store) MarkExpenseEventsUndone(userID uint, batch string, at time.Time) error {
+	result := s.db.Model(&models.ExpenseEvent{}).
+		Where("user_id = ? AND batch = ? AND undone_at IS NULL", userID, batch).
+		Update("undone_at", at)
+	return result.Error
+}
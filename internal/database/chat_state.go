@@ -0,0 +1,29 @@
+package database
+
+import (
+	"SmartExpenseAI/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// GetChatState returns the state of a chat, or nil when it is not in a conversation
+func (s *Store) GetChatState(chatID int64) (*models.ChatState, error) {
+	// Find instead of First: this runs for every message and a missing row is the usual case
+	var states []models.ChatState
+	result := s.db.Where("chat_id = ?", chatID).Limit(1).Find(&states)
+	if result.Error != nil || len(states) == 0 {
+		return nil, result.Error
+	}
+	return &states[0], nil
+}
+
+// SaveChatState creates or replaces the state of a chat
+func (s *Store) SaveChatState(state *models.ChatState) error {
+	result := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(state)
+	return result.Error
+}
+
+func (s *Store) DeleteChatState(chatID int64) error {
+	result := s.db.Where("chat_id = ?", chatID).Delete(&models.ChatState{})
+	return result.Error
+}
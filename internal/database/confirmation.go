@@ -0,0 +1,41 @@
+package database
+
+import (
+	"time"
+
+	"SmartExpenseAI/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// SavePendingConfirmation creates or replaces a pending confirmation
+func (s *Store) SavePendingConfirmation(confirmation *models.PendingConfirmation) error {
+	result := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(confirmation)
+	return result.Error
+}
+
+// TakePendingConfirmation removes an unexpired pending confirmation of the user and returns it,
+// or nil when there is no such confirmation. Only the instance whose delete succeeds gets it,
+// so a confirmation is carried out once even when the button is pressed twice.
+func (s *Store) TakePendingConfirmation(userID uint, key string, now time.Time) (*models.PendingConfirmation, error) {
+	var confirmations []models.PendingConfirmation
+	result := s.db.Where("key = ? AND user_id = ?", key, userID).Limit(1).Find(&confirmations)
+	if result.Error != nil || len(confirmations) == 0 {
+		return nil, result.Error
+	}
+
+	result = s.db.Where("key = ? AND user_id = ?", key, userID).Delete(&models.PendingConfirmation{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 || !confirmations[0].ExpiresAt.After(now) {
+		return nil, nil
+	}
+	return &confirmations[0], nil
+}
+
+// DeleteExpiredPendingConfirmations removes confirmations nobody answered in time
+func (s *Store) DeleteExpiredPendingConfirmations(now time.Time) (int64, error) {
+	result := s.db.Where("expires_at < ?", now).Delete(&models.PendingConfirmation{})
+	return result.RowsAffected, result.Error
+}
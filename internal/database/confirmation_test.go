@@ -0,0 +1,35 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+func TestTakePendingConfirmation(t *testing.T) {
+	store := newTestStore(t)
+	now := time.Now()
+	for _, confirmation := range []models.PendingConfirmation{
+		{Key: "import:1", UserID: 1, Data: `{"Format":"BCA"}`, ExpiresAt: now.Add(time.Minute)},
+		{Key: "duplicate:old", UserID: 1, Data: "{}", ExpiresAt: now.Add(-time.Minute)},
+	} {
+		if err := store.SavePendingConfirmation(&confirmation); err != nil {
+			t.Fatalf("SavePendingConfirmation: %v", err)
+		}
+	}
+
+	if got, err := store.TakePendingConfirmation(2, "import:1", now); err != nil || got != nil {
+		t.Errorf("taken by another user = %v, %v, want nil", got, err)
+	}
+	got, err := store.TakePendingConfirmation(1, "import:1", now)
+	if err != nil || got == nil || got.Data != `{"Format":"BCA"}` {
+		t.Fatalf("TakePendingConfirmation = %v, %v, want the import", got, err)
+	}
+	if got, err := store.TakePendingConfirmation(1, "import:1", now); err != nil || got != nil {
+		t.Errorf("taken twice = %v, %v, want nil", got, err)
+	}
+	if got, err := store.TakePendingConfirmation(1, "duplicate:old", now); err != nil || got != nil {
+		t.Errorf("expired confirmation taken = %v, %v, want nil", got, err)
+	}
+}
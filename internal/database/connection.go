@@ -1,56 +1,139 @@
 package database
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
-	"os"
+	"time"
 
+	"SmartExpenseAI/internal/config"
 	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-var DB *gorm.DB
+// Store is the GORM implementation of repository.Repository
+type Store struct {
+	db      *gorm.DB
+	dialect string
+}
+
+var _ repository.Repository = (*Store)(nil)
+
+// Open connects to the database and, when MigrateOnStart is set, applies pending migrations.
+// URLs starting with sqlite: or file: open an embedded SQLite database, anything else PostgreSQL.
+func Open(cfg config.DatabaseConfig) (*Store, error) {
+	var (
+		db      *gorm.DB
+		dialect string
+		err     error
+	)
+	if dsn, ok := sqliteDSN(cfg.URL); ok {
+		dialect = "sqlite"
+		db, err = openSQLite(dsn)
+	} else {
+		dialect = "postgres"
+		db, err = gorm.Open(postgres.Open(cfg.URL), &gorm.Config{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	store := &Store{db: db, dialect: dialect}
 
-func InitDB() {
-	databaseURL := os.Getenv("DATABASE_URL")
-	if databaseURL == "" {
-		log.Fatal("DATABASE_URL environment variable is not set")
+	if cfg.MigrateOnStart {
+		if _, err := store.Migrate(); err != nil {
+			store.Close()
+			return nil, err
+		}
 	}
 
-	var err error
-	DB, err = gorm.Open(postgres.Open(databaseURL), &gorm.Config{})
+	log.Printf("Database connected successfully (%s)", dialect)
+	return store, nil
+}
+
+// Ping checks that the database is reachable
+func (s *Store) Ping(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return err
 	}
+	return sqlDB.PingContext(ctx)
+}
 
-	// Migrate the schema
-	DB.AutoMigrate(&models.Expense{})
+// Transaction runs fn in a database transaction that is committed when fn returns nil
+func (s *Store) Transaction(fn func(tx repository.Repository) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&Store{db: tx, dialect: s.dialect})
+	})
+}
 
-	log.Println("Database connected successfully")
+// Close closes the database connection pool
+func (s *Store) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
 
-func GetExpensesByUserID(userID uint) ([]models.Expense, error) {
-	var expenses []models.Expense
-	result := DB.Where("user_id = ?", userID).Order("date DESC").Find(&expenses)
-	return expenses, result.Error
+func (s *Store) GetExpenseByID(userID uint, expenseID uint) (*models.Expense, error) {
+	var expense models.Expense
+	result := s.db.Where("user_id = ? AND id = ?", userID, expenseID).First(&expense)
+	if result.Error != nil {
+		return nil, notFound(result.Error)
+	}
+	return &expense, nil
+}
+
+func (s *Store) UpdateExpense(expense *models.Expense) error {
+	result := s.db.Save(expense)
+	return result.Error
+}
+
+func (s *Store) DeleteExpense(userID uint, expenseID uint) error {
+	result := s.db.Where("user_id = ? AND id = ?", userID, expenseID).Delete(&models.Expense{})
+	return result.Error
 }
 
-func GetExpenseByID(userID uint, expenseID uint) (*models.Expense, error) {
+// GetDeletedExpenseByID returns a soft-deleted expense
+func (s *Store) GetDeletedExpenseByID(userID uint, expenseID uint) (*models.Expense, error) {
 	var expense models.Expense
-	result := DB.Where("user_id = ? AND id = ?", userID, expenseID).First(&expense)
+	result := s.db.Unscoped().Where("user_id = ? AND id = ? AND deleted_at IS NOT NULL", userID, expenseID).First(&expense)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, notFound(result.Error)
 	}
 	return &expense, nil
 }
 
-func UpdateExpense(expense *models.Expense) error {
-	result := DB.Save(expense)
+// RestoreExpense brings back a soft-deleted expense
+func (s *Store) RestoreExpense(userID uint, expenseID uint) error {
+	result := s.db.Unscoped().Model(&models.Expense{}).
+		Where("user_id = ? AND id = ?", userID, expenseID).
+		Update("deleted_at", nil)
 	return result.Error
 }
 
-func DeleteExpense(userID uint, expenseID uint) error {
-	result := DB.Where("user_id = ? AND id = ?", userID, expenseID).Delete(&models.Expense{})
+// GetRecentExpensesByAmount returns the user's expenses with the given amount created since the given time
+func (s *Store) GetRecentExpensesByAmount(userID uint, amount float64, since time.Time) ([]models.Expense, error) {
+	var expenses []models.Expense
+	result := s.db.Where("user_id = ? AND amount = ? AND created_at >= ?", userID, amount, since).
+		Order("created_at DESC").
+		Find(&expenses)
+	return expenses, result.Error
+}
+
+func (s *Store) CreateExpense(expense *models.Expense) error {
+	result := s.db.Create(expense)
 	return result.Error
 }
+
+// notFound translates GORM's missing record error to repository.ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repository.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"strings"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// GetExpensesFiltered returns a user's expenses matching the filter, newest first
+func (s *Store) GetExpensesFiltered(userID uint, filter repository.ExpenseFilter) ([]models.Expense, error) {
+	var expenses []models.Expense
+	result := s.filteredExpenses(userID, filter).Order("date DESC").Find(&expenses)
+	return expenses, result.Error
+}
+
+// ListExpensesPage returns one page of a user's expenses matching the filter along with the total count.
+// sortColumn must be a value of ExpenseSortColumns.
+func (s *Store) ListExpensesPage(userID uint, filter repository.ExpenseFilter, sortColumn string, descending bool, limit int, offset int) ([]models.Expense, int64, error) {
+	var total int64
+	if err := s.filteredExpenses(userID, filter).Model(&models.Expense{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := sortColumn
+	if descending {
+		order += " DESC"
+	}
+
+	var expenses []models.Expense
+	result := s.filteredExpenses(userID, filter).
+		Order(order).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&expenses)
+	return expenses, total, result.Error
+}
+
+// ListExpensesByCursor returns up to limit of a user's expenses older than the cursor, or newer when newer
+// is set, ordered newest first. Seeking on (date, id) keeps every page as cheap as the first.
+func (s *Store) ListExpensesByCursor(userID uint, cursor *repository.ExpenseCursor, newer bool, limit int) ([]models.Expense, error) {
+	query := s.db.Where("user_id = ?", userID)
+	ascending := cursor != nil && newer
+	switch {
+	case ascending:
+		// Newer pages are read oldest first to take the rows right after the cursor
+		query = query.Where("(date > ? OR (date = ? AND id > ?))", cursor.Date, cursor.Date, cursor.ID).Order("date ASC, id ASC")
+	case cursor != nil:
+		query = query.Where("(date < ? OR (date = ? AND id < ?))", cursor.Date, cursor.Date, cursor.ID).Order("date DESC, id DESC")
+	default:
+		query = query.Order("date DESC, id DESC")
+	}
+
+	var expenses []models.Expense
+	if err := query.Limit(limit).Find(&expenses).Error; err != nil {
+		return nil, err
+	}
+	if ascending {
+		for i, j := 0, len(expenses)-1; i < j; i, j = i+1, j-1 {
+			expenses[i], expenses[j] = expenses[j], expenses[i]
+		}
+	}
+	return expenses, nil
+}
+
+// GetCategoryTotals returns the number and sum of a user's expenses per category
+func (s *Store) GetCategoryTotals(userID uint, filter repository.ExpenseFilter) ([]repository.CategoryTotal, error) {
+	var totals []repository.CategoryTotal
+	result := s.filteredExpenses(userID, filter).
+		Model(&models.Expense{}).
+		Select("category, COUNT(*) AS count, SUM(amount) AS total").
+		Group("category").
+		Order("total DESC").
+		Scan(&totals)
+	return totals, result.Error
+}
+
+func (s *Store) filteredExpenses(userID uint, filter repository.ExpenseFilter) *gorm.DB {
+	query := s.db.Where("user_id = ?", userID)
+	if filter.From != nil {
+		query = query.Where("date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("date < ?", *filter.To)
+	}
+	if filter.Category != "" {
+		query = query.Where("LOWER(category) = LOWER(?)", filter.Category)
+	}
+	if filter.Tag != "" {
+		query = query.Where(`tags LIKE ? ESCAPE '\'`, "%,"+escapeLike(models.NormalizeTag(filter.Tag))+",%")
+	}
+	if filter.Query != "" {
+		// Matches the trigram indexes on LOWER(description) and LOWER(merchant) in PostgreSQL
+		pattern := "%" + escapeLike(filter.Query) + "%"
+		query = query.Where(`(LOWER(description) LIKE LOWER(?) ESCAPE '\' OR LOWER(merchant) LIKE LOWER(?) ESCAPE '\')`, pattern, pattern)
+	}
+	if filter.MinAmount != nil {
+		query = query.Where("amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where("amount <= ?", *filter.MaxAmount)
+	}
+	return query
+}
+
+// likeEscaper escapes the LIKE wildcards, so that searching for "50%" or "a_b" matches them
+// literally; queries using it must declare ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+func TestGetExpensesFiltered(t *testing.T) {
+	store := newTestStore(t)
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC) }
+
+	createExpense(t, store, models.Expense{UserID: 1, Description: "Kopi susu", Category: "Food", Amount: 25000, Date: day(1), Tags: models.NewTags([]string{"kantor"})})
+	createExpense(t, store, models.Expense{UserID: 1, Description: "Diskon 50% kopi", Category: "food", Amount: 12500, Date: day(2)})
+	createExpense(t, store, models.Expense{UserID: 1, Description: "Ojek", Merchant: "Gojek", Category: "Transport", Amount: 20000, Date: day(3), Tags: models.NewTags([]string{"work_trip"})})
+	createExpense(t, store, models.Expense{UserID: 1, Description: "Hotel", Category: "Travel", Amount: 500000, Date: day(4), Tags: models.NewTags([]string{"workxtrip"})})
+	createExpense(t, store, models.Expense{UserID: 2, Description: "Kopi", Category: "Food", Amount: 30000, Date: day(2)})
+	deleted := createExpense(t, store, models.Expense{UserID: 1, Description: "Kopi batal", Category: "Food", Amount: 25000, Date: day(2)})
+	if err := store.DeleteExpense(1, deleted.ID); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+
+	from, to := day(2), day(4)
+	minAmount, maxAmount := 15000.0, 100000.0
+	tests := []struct {
+		name   string
+		filter repository.ExpenseFilter
+		want   []string
+	}{
+		{"everything of the user, newest first", repository.ExpenseFilter{}, []string{"Hotel", "Ojek", "Diskon 50% kopi", "Kopi susu"}},
+		{"date range excludes the end", repository.ExpenseFilter{From: &from, To: &to}, []string{"Ojek", "Diskon 50% kopi"}},
+		{"category ignores case", repository.ExpenseFilter{Category: "FOOD"}, []string{"Diskon 50% kopi", "Kopi susu"}},
+		{"tag", repository.ExpenseFilter{Tag: "#Kantor"}, []string{"Kopi susu"}},
+		{"underscore in a tag is literal", repository.ExpenseFilter{Tag: "work_trip"}, []string{"Ojek"}},
+		{"query matches description", repository.ExpenseFilter{Query: "KOPI"}, []string{"Diskon 50% kopi", "Kopi susu"}},
+		{"query matches merchant", repository.ExpenseFilter{Query: "gojek"}, []string{"Ojek"}},
+		{"percent in a query is literal", repository.ExpenseFilter{Query: "50%"}, []string{"Diskon 50% kopi"}},
+		{"a lone percent is literal too", repository.ExpenseFilter{Query: "%"}, []string{"Diskon 50% kopi"}},
+		{"amount range", repository.ExpenseFilter{MinAmount: &minAmount, MaxAmount: &maxAmount}, []string{"Ojek", "Kopi susu"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expenses, err := store.GetExpensesFiltered(1, tt.filter)
+			if err != nil {
+				t.Fatalf("GetExpensesFiltered: %v", err)
+			}
+			if got := descriptions(expenses); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListExpensesByCursor(t *testing.T) {
+	store := newTestStore(t)
+	same := time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+
+	// Two expenses share a date, so the ID has to break the tie
+	var ids []uint
+	for _, date := range []time.Time{same.AddDate(0, 0, -1), same, same, same.AddDate(0, 0, 1), same.AddDate(0, 0, 2)} {
+		ids = append(ids, createExpense(t, store, models.Expense{UserID: 1, Description: "x", Amount: 1000, Date: date}).ID)
+	}
+	createExpense(t, store, models.Expense{UserID: 2, Description: "other", Amount: 1000, Date: same})
+	newestFirst := []uint{ids[4], ids[3], ids[2], ids[1], ids[0]}
+
+	// Page through older expenses two at a time
+	var seen []uint
+	var cursor *repository.ExpenseCursor
+	for page := 0; page < 4; page++ {
+		expenses, err := store.ListExpensesByCursor(1, cursor, false, 2)
+		if err != nil {
+			t.Fatalf("ListExpensesByCursor: %v", err)
+		}
+		if len(expenses) == 0 {
+			break
+		}
+		for _, expense := range expenses {
+			seen = append(seen, expense.ID)
+		}
+		last := expenses[len(expenses)-1]
+		cursor = &repository.ExpenseCursor{Date: last.Date, ID: last.ID}
+	}
+	if !slices.Equal(seen, newestFirst) {
+		t.Fatalf("paging older gave %v, want %v", seen, newestFirst)
+	}
+
+	// Newer pages come right after the cursor, still newest first
+	oldest, err := store.GetExpenseByID(1, ids[0])
+	if err != nil {
+		t.Fatalf("GetExpenseByID: %v", err)
+	}
+	expenses, err := store.ListExpensesByCursor(1, &repository.ExpenseCursor{Date: oldest.Date, ID: oldest.ID}, true, 2)
+	if err != nil {
+		t.Fatalf("ListExpensesByCursor newer: %v", err)
+	}
+	if got := expenseIDs(expenses); !slices.Equal(got, []uint{ids[2], ids[1]}) {
+		t.Errorf("newer page = %v, want %v", got, []uint{ids[2], ids[1]})
+	}
+}
+
+func descriptions(expenses []models.Expense) []string {
+	var result []string
+	for _, expense := range expenses {
+		result = append(result, expense.Description)
+	}
+	return result
+}
+
+func expenseIDs(expenses []models.Expense) []uint {
+	var result []uint
+	for _, expense := range expenses {
+		result = append(result, expense.ID)
+	}
+	return result
+}
@@ -0,0 +1,26 @@
+package database
+
+import (
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+// GetExpensesByAmount returns the user's expenses with the given amount dated in the period, oldest first
+func (s *Store) GetExpensesByAmount(userID uint, amount float64, from time.Time, to time.Time) ([]models.Expense, error) {
+	var expenses []models.Expense
+	result := s.db.
+		Where("user_id = ? AND amount = ? AND date >= ? AND date < ?", userID, amount, from, to).
+		Order("date, id").
+		Find(&expenses)
+	return expenses, result.Error
+}
+
+// CreateExpenses saves several expenses in a single transaction
+func (s *Store) CreateExpenses(expenses []models.Expense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+	result := s.db.CreateInBatches(expenses, 100)
+	return result.Error
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+func TestGetExpensesByAmount(t *testing.T) {
+	store := newTestStore(t)
+	day := time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	later := createExpense(t, store, models.Expense{UserID: 1, Description: "Grab pulang", Amount: 20000, Date: day.Add(18 * time.Hour)})
+	earlier := createExpense(t, store, models.Expense{UserID: 1, Description: "Grab kantor", Amount: 20000, Date: day.Add(8 * time.Hour)})
+	createExpense(t, store, models.Expense{UserID: 1, Description: "Kopi", Amount: 25000, Date: day.Add(9 * time.Hour)})
+	createExpense(t, store, models.Expense{UserID: 1, Description: "Grab kemarin", Amount: 20000, Date: day.Add(-time.Hour)})
+	createExpense(t, store, models.Expense{UserID: 2, Description: "Grab", Amount: 20000, Date: day.Add(8 * time.Hour)})
+	deleted := createExpense(t, store, models.Expense{UserID: 1, Description: "Grab batal", Amount: 20000, Date: day.Add(12 * time.Hour)})
+	if err := store.DeleteExpense(1, deleted.ID); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+
+	expenses, err := store.GetExpensesByAmount(1, 20000, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetExpensesByAmount: %v", err)
+	}
+	if len(expenses) != 2 || expenses[0].ID != earlier.ID || expenses[1].ID != later.ID {
+		t.Errorf("GetExpensesByAmount = %v, want expenses %d and %d", expenses, earlier.ID, later.ID)
+	}
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"time"
+
+	"SmartExpenseAI/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RegisterScheduledJob creates the job row if needed and keeps its schedule up to date,
+// without touching the run history
+func (s *Store) RegisterScheduledJob(name string, schedule string) (*models.ScheduledJob, error) {
+	job := models.ScheduledJob{Name: name, Schedule: schedule}
+	result := s.db.Where("name = ?", name).FirstOrCreate(&job)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if job.Schedule != schedule {
+		job.Schedule = schedule
+		if err := s.db.Model(&job).Update("schedule", schedule).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &job, nil
+}
+
+func (s *Store) GetScheduledJob(name string) (*models.ScheduledJob, error) {
+	var job models.ScheduledJob
+	result := s.db.Where("name = ?", name).First(&job)
+	if result.Error != nil {
+		return nil, notFound(result.Error)
+	}
+	return &job, nil
+}
+
+func (s *Store) GetScheduledJobs() ([]models.ScheduledJob, error) {
+	var jobs []models.ScheduledJob
+	result := s.db.Order("name").Find(&jobs)
+	return jobs, result.Error
+}
+
+func (s *Store) DeleteScheduledJob(name string) error {
+	result := s.db.Where("name = ?", name).Delete(&models.ScheduledJob{})
+	return result.Error
+}
+
+// AcquireJobLock takes the lock of a job for the given owner until the given time.
+// It returns false when another instance currently holds an unexpired lock.
+func (s *Store) AcquireJobLock(name string, owner string, until time.Time) (bool, error) {
+	result := s.db.Model(&models.ScheduledJob{}).
+		Where("name = ? AND (locked_until IS NULL OR locked_until < ? OR locked_by = ?)", name, time.Now(), owner).
+		Updates(map[string]interface{}{"locked_by": owner, "locked_until": until})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseJobLock releases the lock of a job without recording a run
+func (s *Store) ReleaseJobLock(name string, owner string) error {
+	result := s.db.Model(&models.ScheduledJob{}).
+		Where("name = ? AND locked_by = ?", name, owner).
+		Updates(map[string]interface{}{"locked_by": "", "locked_until": nil})
+	return result.Error
+}
+
+// FinishJobRun records a completed run and releases the lock held by owner
+func (s *Store) FinishJobRun(name string, owner string, ranAt time.Time, lastError string) error {
+	result := s.db.Model(&models.ScheduledJob{}).
+		Where("name = ? AND locked_by = ?", name, owner).
+		Updates(map[string]interface{}{
+			"last_run_at":  ranAt,
+			"last_error":   lastError,
+			"run_count":    gorm.Expr("run_count + 1"),
+			"locked_by":    "",
+			"locked_until": nil,
+		})
+	return result.Error
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAcquireJobLock(t *testing.T) {
+	store := newTestStore(t)
+	if _, err := store.RegisterScheduledJob("weekly-recap", "0 8 * * 0"); err != nil {
+		t.Fatalf("RegisterScheduledJob: %v", err)
+	}
+	acquire := func(owner string, until time.Time) bool {
+		t.Helper()
+		acquired, err := store.AcquireJobLock("weekly-recap", owner, until)
+		if err != nil {
+			t.Fatalf("AcquireJobLock(%s): %v", owner, err)
+		}
+		return acquired
+	}
+	now := time.Now()
+
+	if !acquire("a", now.Add(time.Minute)) {
+		t.Fatal("a could not lock a free job")
+	}
+	if acquire("b", now.Add(time.Minute)) {
+		t.Error("b locked a job a holds")
+	}
+	if !acquire("a", now.Add(time.Minute)) {
+		t.Error("a could not renew its own lock")
+	}
+
+	if err := store.FinishJobRun("weekly-recap", "a", now, ""); err != nil {
+		t.Fatalf("FinishJobRun: %v", err)
+	}
+	if !acquire("b", now.Add(-time.Second)) {
+		t.Fatal("b could not lock the job after a finished")
+	}
+	// b's lock has already expired, as if b crashed
+	if !acquire("a", now.Add(time.Minute)) {
+		t.Error("a could not take over an expired lock")
+	}
+
+	job, err := store.GetScheduledJob("weekly-recap")
+	if err != nil {
+		t.Fatalf("GetScheduledJob: %v", err)
+	}
+	if job.RunCount != 1 || job.LastRunAt == nil || job.LockedBy != "a" {
+		t.Errorf("job run count %d, last run %v, locked by %q, want 1 run and locked by a", job.RunCount, job.LastRunAt, job.LockedBy)
+	}
+
+	if acquired, err := store.AcquireJobLock("unknown", "a", now.Add(time.Minute)); err != nil || acquired {
+		t.Errorf("AcquireJobLock of an unknown job = %v, %v, want false", acquired, err)
+	}
+}
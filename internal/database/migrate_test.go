@@ -0,0 +1,98 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"SmartExpenseAI/internal/config"
+)
+
+func TestMigrationsMatchAcrossDialects(t *testing.T) {
+	postgres, err := loadMigrations("postgres")
+	if err != nil {
+		t.Fatalf("loading postgres migrations: %v", err)
+	}
+	sqlite, err := loadMigrations("sqlite")
+	if err != nil {
+		t.Fatalf("loading sqlite migrations: %v", err)
+	}
+
+	if len(postgres) != len(sqlite) {
+		t.Fatalf("postgres has %d migrations, sqlite %d", len(postgres), len(sqlite))
+	}
+	for i := range postgres {
+		if postgres[i].Version != sqlite[i].Version || postgres[i].Name != sqlite[i].Name {
+			t.Errorf("migration %d is %d_%s in postgres but %d_%s in sqlite", i,
+				postgres[i].Version, postgres[i].Name, sqlite[i].Version, sqlite[i].Name)
+		}
+		if postgres[i].Version != i+1 {
+			t.Errorf("migration %d_%s breaks the numbering, want version %d", postgres[i].Version, postgres[i].Name, i+1)
+		}
+	}
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	store, err := Open(config.DatabaseConfig{URL: "sqlite::memory:"})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer store.Close()
+
+	migrations, err := loadMigrations(store.dialect)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+
+	applied, err := store.Migrate()
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if len(applied) != len(migrations) {
+		t.Fatalf("Migrate applied %d migrations, want %d", len(applied), len(migrations))
+	}
+	if again, err := store.Migrate(); err != nil || len(again) != 0 {
+		t.Fatalf("second Migrate applied %d migrations, err %v, want none", len(again), err)
+	}
+	for _, table := range []string{"expenses", "expense_events", "chat_states", "pending_confirmations"} {
+		if !store.db.Migrator().HasTable(table) {
+			t.Errorf("table %s missing after Migrate", table)
+		}
+	}
+
+	// Everything but the baseline rolls back, in reverse order
+	rolledBack, err := store.Rollback(len(migrations) - 1)
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if len(rolledBack) != len(migrations)-1 || rolledBack[0].Version != len(migrations) {
+		t.Fatalf("Rollback reverted %v, want every migration after the baseline, newest first", rolledBack)
+	}
+	for _, table := range []string{"expense_events", "chat_states", "pending_confirmations"} {
+		if store.db.Migrator().HasTable(table) {
+			t.Errorf("table %s still exists after rolling back", table)
+		}
+	}
+
+	// The baseline may have adopted existing tables, so it is never rolled back
+	if _, err := store.Rollback(1); err == nil || !strings.Contains(err.Error(), "baseline") {
+		t.Errorf("rolling back the baseline: err = %v, want a refusal", err)
+	}
+	if !store.db.Migrator().HasTable("expenses") {
+		t.Error("rolling back the baseline dropped the expenses table")
+	}
+
+	statuses, err := store.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	for _, status := range statuses {
+		if applied := status.AppliedAt != nil; applied != (status.Version == baselineVersion) {
+			t.Errorf("migration %d_%s applied = %v after rolling back", status.Version, status.Name, applied)
+		}
+	}
+
+	// And everything applies again on top of the baseline
+	if reapplied, err := store.Migrate(); err != nil || len(reapplied) != len(migrations)-1 {
+		t.Fatalf("Migrate after rolling back applied %d migrations, err %v, want %d", len(reapplied), err, len(migrations)-1)
+	}
+}
@@ -0,0 +1,4 @@
+-- The baseline adopts tables that may predate migrations (IF NOT EXISTS), so rolling it
+-- back could drop data it never created. Rollback refuses to run it; this file only exists
+-- because every migration needs a down file.
+SELECT 1;
@@ -0,0 +1,81 @@
+-- Tables as previously created by AutoMigrate; IF NOT EXISTS keeps existing databases intact
+CREATE TABLE IF NOT EXISTS expenses (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    description text,
+    category text,
+    amount decimal,
+    date timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_expenses_deleted_at ON expenses (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    daily_budget decimal,
+    digest_enabled boolean,
+    digest_time text,
+    reminder_days bigint,
+    last_reminder_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_preferences_user_id ON user_preferences (user_id);
+CREATE INDEX IF NOT EXISTS idx_user_preferences_deleted_at ON user_preferences (deleted_at);
+
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    name text PRIMARY KEY,
+    schedule text,
+    last_run_at timestamptz,
+    last_error text,
+    run_count bigint,
+    locked_by text,
+    locked_until timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS processed_updates (
+    update_id bigint PRIMARY KEY,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_processed_updates_created_at ON processed_updates (created_at);
+
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    prefix text,
+    token_hash text NOT NULL,
+    last_used_at timestamptz,
+    created_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_tokens_token_hash ON api_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_deleted_at ON api_tokens (deleted_at);
+
+CREATE TABLE IF NOT EXISTS login_tokens (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    token_hash text NOT NULL,
+    expires_at timestamptz,
+    used_at timestamptz,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_login_tokens_token_hash ON login_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS web_sessions (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    token_hash text NOT NULL,
+    csrf_token text NOT NULL,
+    expires_at timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_web_sessions_user_id ON web_sessions (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_web_sessions_token_hash ON web_sessions (token_hash);
+CREATE INDEX IF NOT EXISTS idx_web_sessions_expires_at ON web_sessions (expires_at);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_expenses_user_id_date;
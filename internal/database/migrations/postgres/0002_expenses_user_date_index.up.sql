@@ -0,0 +1,2 @@
+-- Every expense query filters by user and most of them by date
+CREATE INDEX IF NOT EXISTS idx_expenses_user_id_date ON expenses (user_id, date);
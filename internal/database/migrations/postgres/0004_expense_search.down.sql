@@ -0,0 +1,7 @@
+-- pg_trgm is left installed, other objects may depend on it
+DROP INDEX IF EXISTS idx_expenses_tags_trgm;
+DROP INDEX IF EXISTS idx_expenses_merchant_trgm;
+DROP INDEX IF EXISTS idx_expenses_description_trgm;
+
+ALTER TABLE expenses DROP COLUMN IF EXISTS tags;
+ALTER TABLE expenses DROP COLUMN IF EXISTS merchant;
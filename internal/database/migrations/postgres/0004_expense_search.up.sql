@@ -0,0 +1,9 @@
+-- Merchant and tags for /cari, with trigram indexes so LOWER(column) LIKE '%term%' searches stay fast
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS merchant text NOT NULL DEFAULT '';
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS tags text NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_expenses_description_trgm ON expenses USING gin (lower(description) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_expenses_merchant_trgm ON expenses USING gin (lower(merchant) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_expenses_tags_trgm ON expenses USING gin (tags gin_trgm_ops);
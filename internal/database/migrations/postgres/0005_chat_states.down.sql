@@ -0,0 +1,3 @@
+ALTER TABLE expenses DROP COLUMN IF EXISTS account;
+
+DROP TABLE IF EXISTS chat_states;
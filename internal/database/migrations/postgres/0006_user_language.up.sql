@@ -0,0 +1,2 @@
+-- Language the bot replies in, detected from the Telegram client on first contact
+ALTER TABLE user_preferences ADD COLUMN IF NOT EXISTS language text NOT NULL DEFAULT '';
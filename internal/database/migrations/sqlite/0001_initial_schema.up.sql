@@ -0,0 +1,81 @@
+-- Same tables as the PostgreSQL schema in SQLite types; times are stored as UTC text
+CREATE TABLE IF NOT EXISTS expenses (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id bigint NOT NULL,
+    description text,
+    category text,
+    amount real,
+    date datetime,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_expenses_deleted_at ON expenses (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id bigint NOT NULL,
+    daily_budget real,
+    digest_enabled boolean,
+    digest_time text,
+    reminder_days bigint,
+    last_reminder_at datetime,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_preferences_user_id ON user_preferences (user_id);
+CREATE INDEX IF NOT EXISTS idx_user_preferences_deleted_at ON user_preferences (deleted_at);
+
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    name text PRIMARY KEY,
+    schedule text,
+    last_run_at datetime,
+    last_error text,
+    run_count bigint,
+    locked_by text,
+    locked_until datetime,
+    created_at datetime,
+    updated_at datetime
+);
+
+CREATE TABLE IF NOT EXISTS processed_updates (
+    update_id bigint PRIMARY KEY,
+    created_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_processed_updates_created_at ON processed_updates (created_at);
+
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id bigint NOT NULL,
+    prefix text,
+    token_hash text NOT NULL,
+    last_used_at datetime,
+    created_at datetime,
+    deleted_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_tokens_token_hash ON api_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_deleted_at ON api_tokens (deleted_at);
+
+CREATE TABLE IF NOT EXISTS login_tokens (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id bigint NOT NULL,
+    token_hash text NOT NULL,
+    expires_at datetime,
+    used_at datetime,
+    created_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_login_tokens_token_hash ON login_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS web_sessions (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id bigint NOT NULL,
+    token_hash text NOT NULL,
+    csrf_token text NOT NULL,
+    expires_at datetime,
+    created_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_web_sessions_user_id ON web_sessions (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_web_sessions_token_hash ON web_sessions (token_hash);
+CREATE INDEX IF NOT EXISTS idx_web_sessions_expires_at ON web_sessions (expires_at);
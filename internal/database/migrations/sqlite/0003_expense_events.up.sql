@@ -0,0 +1,15 @@
+-- Audit log of expense changes, used by /undo and /riwayat
+CREATE TABLE IF NOT EXISTS expense_events (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id bigint NOT NULL,
+    expense_id bigint NOT NULL,
+    batch text NOT NULL,
+    action text NOT NULL,
+    before text,
+    after text,
+    undone_at datetime,
+    created_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_expense_events_user_id ON expense_events (user_id);
+CREATE INDEX IF NOT EXISTS idx_expense_events_expense_id ON expense_events (expense_id);
+CREATE INDEX IF NOT EXISTS idx_expense_events_user_id_batch ON expense_events (user_id, batch);
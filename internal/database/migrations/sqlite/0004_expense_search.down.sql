@@ -0,0 +1,2 @@
+ALTER TABLE expenses DROP COLUMN tags;
+ALTER TABLE expenses DROP COLUMN merchant;
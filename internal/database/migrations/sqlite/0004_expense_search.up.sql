@@ -0,0 +1,3 @@
+-- Merchant and tags for /cari; SQLite has no trigram indexes, searches scan the user's rows
+ALTER TABLE expenses ADD COLUMN merchant text NOT NULL DEFAULT '';
+ALTER TABLE expenses ADD COLUMN tags text NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE expenses DROP COLUMN account;
+
+DROP TABLE IF EXISTS chat_states;
@@ -0,0 +1,11 @@
+-- Per-chat conversation state of the guided edit, and the account an expense was paid from
+CREATE TABLE IF NOT EXISTS chat_states (
+    chat_id bigint PRIMARY KEY,
+    flow text NOT NULL,
+    step text NOT NULL,
+    data text NOT NULL DEFAULT '',
+    expires_at datetime NOT NULL,
+    updated_at datetime
+);
+
+ALTER TABLE expenses ADD COLUMN account text NOT NULL DEFAULT '';
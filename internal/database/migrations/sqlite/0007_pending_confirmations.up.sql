@@ -0,0 +1,10 @@
+-- Previewed imports and duplicate questions waiting for a button, shared by all instances
+CREATE TABLE IF NOT EXISTS pending_confirmations (
+    key text PRIMARY KEY,
+    user_id bigint NOT NULL,
+    data text NOT NULL,
+    expires_at datetime NOT NULL,
+    created_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_pending_confirmations_user_id ON pending_confirmations (user_id);
+CREATE INDEX IF NOT EXISTS idx_pending_confirmations_expires_at ON pending_confirmations (expires_at);
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"errors"
+
+	"SmartExpenseAI/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GetUserPreference returns the preferences for a user, creating the default row if none exists yet
+func (s *Store) GetUserPreference(userID uint) (*models.UserPreference, error) {
+	pref := models.UserPreference{UserID: userID, DigestTime: "21:00"}
+	result := s.db.Where("user_id = ?", userID).FirstOrCreate(&pref)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &pref, nil
+}
+
+func (s *Store) SaveUserPreference(pref *models.UserPreference) error {
+	result := s.db.Save(pref)
+	return result.Error
+}
+
+// GetDigestPreferences returns every preference row that has the daily digest enabled
+func (s *Store) GetDigestPreferences() ([]models.UserPreference, error) {
+	var prefs []models.UserPreference
+	result := s.db.Where("digest_enabled = ?", true).Find(&prefs)
+	return prefs, result.Error
+}
+
+// GetReminderPreferences returns every preference row that has the inactivity reminder enabled
+func (s *Store) GetReminderPreferences() ([]models.UserPreference, error) {
+	var prefs []models.UserPreference
+	result := s.db.Where("reminder_days > 0").Find(&prefs)
+	return prefs, result.Error
+}
+
+// GetLastExpense returns the most recently logged expense of a user, or nil if there is none
+func (s *Store) GetLastExpense(userID uint) (*models.Expense, error) {
+	var expense models.Expense
+	result := s.db.Where("user_id = ?", userID).Order("created_at DESC").First(&expense)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &expense, nil
+}
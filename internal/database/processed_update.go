@@ -0,0 +1,30 @@
+package database
+
+import (
+	"time"
+
+	"SmartExpenseAI/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// MarkUpdateProcessed records an update_id and reports whether it was seen for the first time
+func (s *Store) MarkUpdateProcessed(updateID int) (bool, error) {
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ProcessedUpdate{UpdateID: updateID})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UnmarkUpdateProcessed forgets an update_id so a redelivery of it is processed
+func (s *Store) UnmarkUpdateProcessed(updateID int) error {
+	result := s.db.Where("update_id = ?", updateID).Delete(&models.ProcessedUpdate{})
+	return result.Error
+}
+
+// DeleteProcessedUpdatesBefore forgets update_ids older than the given time
+func (s *Store) DeleteProcessedUpdatesBefore(before time.Time) (int64, error) {
+	result := s.db.Where("created_at < ?", before).Delete(&models.ProcessedUpdate{})
+	return result.RowsAffected, result.Error
+}
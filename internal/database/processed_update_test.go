@@ -0,0 +1,43 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarkUpdateProcessed(t *testing.T) {
+	store := newTestStore(t)
+	mark := func(updateID int) bool {
+		t.Helper()
+		first, err := store.MarkUpdateProcessed(updateID)
+		if err != nil {
+			t.Fatalf("MarkUpdateProcessed(%d): %v", updateID, err)
+		}
+		return first
+	}
+
+	if !mark(100) {
+		t.Error("a new update was reported as seen")
+	}
+	if mark(100) {
+		t.Error("a retried update was reported as new")
+	}
+	if !mark(101) {
+		t.Error("another update was reported as seen")
+	}
+
+	if err := store.UnmarkUpdateProcessed(100); err != nil {
+		t.Fatalf("UnmarkUpdateProcessed: %v", err)
+	}
+	if !mark(100) {
+		t.Error("an unmarked update was still reported as seen")
+	}
+
+	deleted, err := store.DeleteProcessedUpdatesBefore(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("DeleteProcessedUpdatesBefore: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("DeleteProcessedUpdatesBefore deleted %d, want 2", deleted)
+	}
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+func (s *Store) CreateLoginToken(token *models.LoginToken) error {
+	result := s.db.Create(token)
+	return result.Error
+}
+
+// UseLoginToken marks an unexpired, unused login token as used and returns it.
+// The conditional update makes sure a link can only be redeemed once.
+func (s *Store) UseLoginToken(tokenHash string, now time.Time) (*models.LoginToken, error) {
+	result := s.db.Model(&models.LoginToken{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	var token models.LoginToken
+	if err := s.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (s *Store) CreateWebSession(session *models.WebSession) error {
+	result := s.db.Create(session)
+	return result.Error
+}
+
+// GetWebSession returns the unexpired session with the given token hash
+func (s *Store) GetWebSession(tokenHash string, now time.Time) (*models.WebSession, error) {
+	var session models.WebSession
+	result := s.db.Where("token_hash = ? AND expires_at > ?", tokenHash, now).First(&session)
+	if result.Error != nil {
+		return nil, notFound(result.Error)
+	}
+	return &session, nil
+}
+
+func (s *Store) DeleteWebSession(tokenHash string) error {
+	result := s.db.Where("token_hash = ?", tokenHash).Delete(&models.WebSession{})
+	return result.Error
+}
+
+// DeleteExpiredLogins removes expired login tokens and sessions
+func (s *Store) DeleteExpiredLogins(now time.Time) error {
+	if err := s.db.Where("expires_at < ?", now).Delete(&models.LoginToken{}).Error; err != nil {
+		return err
+	}
+	return s.db.Where("expires_at < ?", now).Delete(&models.WebSession{}).Error
+}
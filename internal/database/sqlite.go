@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteDSN returns the SQLite file for DATABASE_URL values like sqlite://data/expenses.db,
+// sqlite::memory: or file:expenses.db, and false for anything else
+func sqliteDSN(url string) (string, bool) {
+	switch {
+	case strings.HasPrefix(url, "sqlite://"):
+		return strings.TrimPrefix(url, "sqlite://"), true
+	case strings.HasPrefix(url, "sqlite:"):
+		return strings.TrimPrefix(url, "sqlite:"), true
+	case strings.HasPrefix(url, "file:"):
+		return url, true
+	}
+	return "", false
+}
+
+// openSQLite opens an embedded SQLite database. SQLite stores times as text, so every
+// time argument is written in UTC to keep range comparisons on those columns correct.
+func openSQLite(dsn string) (*gorm.DB, error) {
+	sqlDB, err := sql.Open(sqlite.DriverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	// A single connection serializes writes and keeps :memory: databases alive
+	sqlDB.SetMaxOpenConns(1)
+
+	db, err := gorm.Open(sqlite.Dialector{Conn: &utcConnPool{sqlDB}}, &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	if err := db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// utcConnPool passes queries to the underlying pool with time arguments converted to UTC
+type utcConnPool struct {
+	db *sql.DB
+}
+
+func (p *utcConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.db.PrepareContext(ctx, query)
+}
+
+func (p *utcConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.db.ExecContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.db.QueryContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.db.QueryRowContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	tx, err := p.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &utcTx{tx}, nil
+}
+
+// GetDBConn lets gorm.DB.DB() reach the pool for Ping and Close
+func (p *utcConnPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+// utcTx is the transaction counterpart of utcConnPool
+type utcTx struct {
+	tx *sql.Tx
+}
+
+func (t *utcTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.tx.PrepareContext(ctx, query)
+}
+
+func (t *utcTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, query, utcArgs(args)...)
+}
+
+func (t *utcTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, query, utcArgs(args)...)
+}
+
+func (t *utcTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.tx.QueryRowContext(ctx, query, utcArgs(args)...)
+}
+
+func (t *utcTx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *utcTx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// utcArgs converts time.Time arguments, including pointers and valuers such as
+// gorm.DeletedAt, to UTC
+func utcArgs(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		converted[i] = arg
+		switch v := arg.(type) {
+		case time.Time:
+			converted[i] = v.UTC()
+		case *time.Time:
+			if v != nil {
+				converted[i] = v.UTC()
+			}
+		case driver.Valuer:
+			if value, err := v.Value(); err == nil {
+				if t, ok := value.(time.Time); ok {
+					converted[i] = t.UTC()
+				}
+			}
+		}
+	}
+	return converted
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/config"
+	"SmartExpenseAI/internal/models"
+)
+
+// newTestStore opens an empty in-memory SQLite store with every migration applied
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := Open(config.DatabaseConfig{URL: "sqlite::memory:", MigrateOnStart: true})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// createExpense stores an expense, failing the test on error
+func createExpense(t *testing.T, store *Store, expense models.Expense) models.Expense {
+	t.Helper()
+
+	if expense.Date.IsZero() {
+		expense.Date = time.Now()
+	}
+	if err := store.CreateExpense(&expense); err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	return expense
+}
@@ -0,0 +1,149 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// ErrClosed is returned by Submit once Shutdown has been called
+var ErrClosed = errors.New("dispatcher is shut down")
+
+// Handler processes a single update; ctx carries the per-update deadline
+type Handler func(ctx context.Context, update tgbotapi.Update)
+
+// Dispatcher processes updates on a fixed number of workers. Updates of the same
+// chat always go to the same worker, so they are handled in the order received.
+type Dispatcher struct {
+	handler Handler
+	timeout time.Duration
+	queues  []chan tgbotapi.Update
+
+	// done is closed by Shutdown to wake Submit calls waiting for room in a queue
+	done chan struct{}
+	// sending counts Submit calls that may still send; queues are closed once it drops to zero
+	sending   sync.WaitGroup
+	closeOnce sync.Once
+
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// New starts workers goroutines, each with a queue of queueSize pending updates
+func New(workers int, queueSize int, timeout time.Duration, handler Handler) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+
+	d := &Dispatcher{
+		handler: handler,
+		timeout: timeout,
+		queues:  make([]chan tgbotapi.Update, workers),
+		done:    make(chan struct{}),
+	}
+
+	for i := range d.queues {
+		d.queues[i] = make(chan tgbotapi.Update, queueSize)
+		d.wg.Add(1)
+		go d.work(d.queues[i])
+	}
+
+	return d
+}
+
+// Submit queues an update, waiting for room in its worker's queue until ctx is done
+// or the dispatcher shuts down
+func (d *Dispatcher) Submit(ctx context.Context, update tgbotapi.Update) error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return ErrClosed
+	}
+	d.sending.Add(1)
+	d.mu.Unlock()
+	defer d.sending.Done()
+
+	queue := d.queues[d.shard(update)]
+	select {
+	case queue <- update:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-d.done:
+		return ErrClosed
+	}
+}
+
+// Shutdown stops accepting updates and waits until the queued ones are processed or ctx is done
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.done)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		// Waiting senders return once done is closed; only then is closing the queues safe
+		d.sending.Wait()
+		d.closeOnce.Do(func() {
+			for _, queue := range d.queues {
+				close(queue)
+			}
+		})
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) work(queue chan tgbotapi.Update) {
+	defer d.wg.Done()
+
+	for update := range queue {
+		d.process(update)
+	}
+}
+
+// process runs the handler with a deadline, keeping the worker alive if it panics
+func (d *Dispatcher) process(update tgbotapi.Update) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic while handling update %d: %v\n%s", update.UpdateID, r, debug.Stack())
+		}
+	}()
+
+	d.handler(ctx, update)
+}
+
+// shard picks the worker of the update's chat
+func (d *Dispatcher) shard(update tgbotapi.Update) int {
+	key := int64(update.UpdateID)
+	switch {
+	case update.Message != nil && update.Message.Chat != nil:
+		key = update.Message.Chat.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil && update.CallbackQuery.Message.Chat != nil:
+		key = update.CallbackQuery.Message.Chat.ID
+	}
+
+	if key < 0 {
+		key = -key
+	}
+	return int(key % int64(len(d.queues)))
+}
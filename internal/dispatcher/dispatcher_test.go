@@ -0,0 +1,238 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// message returns an update carrying a message of the chat
+func message(updateID int, chatID int64) tgbotapi.Update {
+	return tgbotapi.Update{UpdateID: updateID, Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: chatID}}}
+}
+
+// shutdown shuts the dispatcher down, failing the test if the queued updates are not done in time
+func shutdown(t *testing.T, d *Dispatcher) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
+
+func TestUpdatesOfAChatKeepTheirOrder(t *testing.T) {
+	var mu sync.Mutex
+	handled := make(map[int64][]int)
+	d := New(4, 8, time.Second, func(ctx context.Context, update tgbotapi.Update) {
+		// Uneven work so that workers interleave
+		time.Sleep(time.Duration(update.UpdateID%3) * 100 * time.Microsecond)
+		mu.Lock()
+		defer mu.Unlock()
+		handled[update.Message.Chat.ID] = append(handled[update.Message.Chat.ID], update.UpdateID)
+	})
+
+	chats := []int64{1, 2, 3, -100200300, 7}
+	const perChat = 50
+	for i := 0; i < perChat; i++ {
+		for _, chat := range chats {
+			if err := d.Submit(context.Background(), message(i, chat)); err != nil {
+				t.Fatalf("Submit: %v", err)
+			}
+		}
+	}
+	shutdown(t, d)
+
+	for _, chat := range chats {
+		ids := handled[chat]
+		if len(ids) != perChat {
+			t.Fatalf("chat %d: %d updates handled, want %d", chat, len(ids), perChat)
+		}
+		for i, id := range ids {
+			if id != i {
+				t.Fatalf("chat %d: updates handled in order %v", chat, ids)
+			}
+		}
+	}
+}
+
+func TestCallbacksShareTheWorkerOfTheirChat(t *testing.T) {
+	d := New(8, 1, time.Second, func(context.Context, tgbotapi.Update) {})
+	defer shutdown(t, d)
+
+	callback := tgbotapi.Update{UpdateID: 99, CallbackQuery: &tgbotapi.CallbackQuery{Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 13}}}}
+	if got, want := d.shard(callback), d.shard(message(1, 13)); got != want {
+		t.Errorf("callback goes to worker %d, messages of its chat to %d", got, want)
+	}
+}
+
+func TestPanicKeepsTheWorkerAlive(t *testing.T) {
+	var mu sync.Mutex
+	var handled []int
+	d := New(1, 4, time.Second, func(ctx context.Context, update tgbotapi.Update) {
+		if update.UpdateID == 1 {
+			panic("handler bug")
+		}
+		mu.Lock()
+		handled = append(handled, update.UpdateID)
+		mu.Unlock()
+	})
+
+	for id := 1; id <= 3; id++ {
+		if err := d.Submit(context.Background(), message(id, 5)); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+	shutdown(t, d)
+
+	if len(handled) != 2 || handled[0] != 2 || handled[1] != 3 {
+		t.Errorf("handled %v after a panic, want [2 3]", handled)
+	}
+}
+
+func TestHandlerGetsDeadline(t *testing.T) {
+	deadlines := make(chan time.Duration, 1)
+	d := New(1, 1, time.Minute, func(ctx context.Context, update tgbotapi.Update) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			deadlines <- 0
+			return
+		}
+		deadlines <- time.Until(deadline)
+	})
+
+	if err := d.Submit(context.Background(), message(1, 1)); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	shutdown(t, d)
+
+	if left := <-deadlines; left <= 0 || left > time.Minute {
+		t.Errorf("handler deadline is %v away, want within a minute", left)
+	}
+}
+
+func TestShutdownDrainsQueuedUpdates(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	handled := 0
+	d := New(1, 10, time.Second, func(context.Context, tgbotapi.Update) {
+		<-release
+		mu.Lock()
+		handled++
+		mu.Unlock()
+	})
+
+	for id := 1; id <= 5; id++ {
+		if err := d.Submit(context.Background(), message(id, 1)); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- d.Shutdown(context.Background()) }()
+
+	select {
+	case err := <-stopped:
+		t.Fatalf("Shutdown returned %v before the queued updates were handled", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return after the updates were handled")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if handled != 5 {
+		t.Errorf("%d updates handled before Shutdown returned, want 5", handled)
+	}
+}
+
+func TestShutdownGivesUpWhenContextIsDone(t *testing.T) {
+	release := make(chan struct{})
+	d := New(1, 1, time.Second, func(context.Context, tgbotapi.Update) { <-release })
+	defer close(release)
+
+	if err := d.Submit(context.Background(), message(1, 1)); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown with a stuck handler: err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestSubmitAfterShutdown(t *testing.T) {
+	d := New(2, 1, time.Second, func(context.Context, tgbotapi.Update) {})
+	shutdown(t, d)
+
+	if err := d.Submit(context.Background(), message(1, 1)); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit after Shutdown: err = %v, want ErrClosed", err)
+	}
+	// Shutting down twice is harmless
+	shutdown(t, d)
+}
+
+func TestSubmitWaitingForRoom(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	d := New(1, 1, time.Second, func(context.Context, tgbotapi.Update) {
+		started <- struct{}{}
+		<-release
+	})
+
+	// The worker holds the first update and the queue holds the second
+	if err := d.Submit(context.Background(), message(1, 1)); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	<-started
+	if err := d.Submit(context.Background(), message(2, 1)); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.Submit(ctx, message(3, 1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Submit to a full queue: err = %v, want context.DeadlineExceeded", err)
+	}
+
+	// A sender waiting for room is woken by Shutdown instead of blocking it
+	waiting := make(chan error, 1)
+	go func() { waiting <- d.Submit(context.Background(), message(4, 1)) }()
+	time.Sleep(20 * time.Millisecond)
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- d.Shutdown(context.Background()) }()
+
+	select {
+	case err := <-waiting:
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("waiting Submit: err = %v, want ErrClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiting Submit was not woken by Shutdown")
+	}
+
+	close(release)
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Errorf("Shutdown: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return")
+	}
+}
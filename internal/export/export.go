@@ -0,0 +1,186 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"SmartExpenseAI/internal/i18n"
+	"SmartExpenseAI/internal/models"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ParseFormat normalizes a user supplied format name
+func ParseFormat(name string) (string, error) {
+	switch name {
+	case "csv":
+		return FormatCSV, nil
+	case "xlsx", "excel", "xls":
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q", name)
+}
+
+// FileName builds the name of an export file for the given format in the printer's language
+func FileName(format string, now time.Time, p *i18n.Printer) string {
+	return fmt.Sprintf("%s-%s.%s", p.T("export.file_name"), now.In(p.Location()).Format("20060102-150405"), format)
+}
+
+// ContentType returns the MIME type of an export format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Render writes the expenses in the given format, with the headers in the printer's language
+// and the dates in its location
+func Render(format string, expenses []models.Expense, p *i18n.Printer) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+	switch format {
+	case FormatCSV:
+		err = WriteCSV(&buf, expenses, p)
+	case FormatXLSX:
+		err = WriteXLSX(&buf, expenses, p)
+	default:
+		err = fmt.Errorf("unsupported export format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func header(p *i18n.Printer) []string {
+	return []string{
+		p.T("export.column.id"),
+		p.T("export.column.date"),
+		p.T("export.column.description"),
+		p.T("export.column.category"),
+		p.T("export.column.amount"),
+	}
+}
+
+// row writes an expense, dated on its day in the printer's location
+func row(expense models.Expense, p *i18n.Printer) []string {
+	return []string{
+		strconv.FormatUint(uint64(expense.ID), 10),
+		expense.Date.In(p.Location()).Format("2006-01-02"),
+		expense.Description,
+		expense.Category,
+		strconv.FormatFloat(expense.Amount, 'f', -1, 64),
+	}
+}
+
+// WriteCSV writes the expenses as a CSV file with a header row
+func WriteCSV(w io.Writer, expenses []models.Expense, p *i18n.Printer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header(p)); err != nil {
+		return err
+	}
+	for _, expense := range expenses {
+		if err := writer.Write(row(expense, p)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteXLSX writes the expenses as a single-sheet Excel workbook
+func WriteXLSX(w io.Writer, expenses []models.Expense, p *i18n.Printer) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", workbookXML(p.T("export.sheet"))},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+		{"xl/worksheets/sheet1.xml", sheetXML(expenses, p)},
+	}
+
+	for _, file := range files {
+		f, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, file.content); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// sheetXML renders the worksheet, keeping the amount column numeric
+func sheetXML(expenses []models.Expense, p *i18n.Printer) string {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	writeRow := func(index int, cells []string, numeric map[int]bool) {
+		fmt.Fprintf(&buf, `<row r="%d">`, index)
+		for col, value := range cells {
+			ref := fmt.Sprintf("%c%d", 'A'+col, index)
+			if numeric[col] {
+				fmt.Fprintf(&buf, `<c r="%s"><v>%s</v></c>`, ref, value)
+				continue
+			}
+			fmt.Fprintf(&buf, `<c r="%s" t="inlineStr"><is><t>`, ref)
+			xml.EscapeText(&buf, []byte(value))
+			buf.WriteString(`</t></is></c>`)
+		}
+		buf.WriteString(`</row>`)
+	}
+
+	writeRow(1, header(p), nil)
+	for i, expense := range expenses {
+		writeRow(i+2, row(expense, p), map[int]bool{0: true, 4: true})
+	}
+
+	buf.WriteString(`</sheetData></worksheet>`)
+	return buf.String()
+}
+
+const contentTypesXML = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const rootRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// workbookXML lists the single worksheet under the given name
+func workbookXML(sheet string) string {
+	var name bytes.Buffer
+	xml.EscapeText(&name, []byte(sheet))
+	return xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+}
+
+const workbookRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
@@ -0,0 +1,129 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/i18n"
+	"SmartExpenseAI/internal/importer"
+	"SmartExpenseAI/internal/models"
+)
+
+var jakarta = time.FixedZone("WIB", 7*60*60)
+
+func TestWriteCSVDatesInLocation(t *testing.T) {
+	// Logged just after midnight WIB, which is still the previous day in UTC
+	expense := models.Expense{
+		ID:          3,
+		Description: "Kopi",
+		Category:    "Makanan",
+		Amount:      25000,
+		Date:        time.Date(2026, 10, 19, 0, 30, 0, 0, jakarta).UTC(),
+	}
+
+	tests := []struct {
+		lang   i18n.Lang
+		header []string
+	}{
+		{i18n.Indonesian, []string{"ID", "Tanggal", "Deskripsi", "Kategori", "Jumlah"}},
+		{i18n.English, []string{"ID", "Date", "Description", "Category", "Amount"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteCSV(&buf, []models.Expense{expense}, i18n.NewPrinter(tt.lang, jakarta)); err != nil {
+				t.Fatalf("WriteCSV: %v", err)
+			}
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("reading CSV: %v", err)
+			}
+			if len(records) != 2 {
+				t.Fatalf("got %d records, want 2", len(records))
+			}
+			if strings.Join(records[0], ",") != strings.Join(tt.header, ",") {
+				t.Errorf("header = %v, want %v", records[0], tt.header)
+			}
+			want := []string{"3", "2026-10-19", "Kopi", "Makanan", "25000"}
+			if strings.Join(records[1], ",") != strings.Join(want, ",") {
+				t.Errorf("row = %v, want %v", records[1], want)
+			}
+		})
+	}
+}
+
+func TestFileName(t *testing.T) {
+	now := time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		lang i18n.Lang
+		want string
+	}{
+		{i18n.Indonesian, "pengeluaran-20261019-060000.xlsx"},
+		{i18n.English, "expenses-20261019-060000.xlsx"},
+	}
+
+	for _, tt := range tests {
+		if got := FileName(FormatXLSX, now, i18n.NewPrinter(tt.lang, jakarta)); got != tt.want {
+			t.Errorf("FileName(%s) = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestWriteXLSXSheetName(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, nil, i18n.NewPrinter(i18n.English, jakarta)); err != nil {
+		t.Fatalf("WriteXLSX: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("opening workbook: %v", err)
+	}
+	f, err := archive.Open("xl/workbook.xml")
+	if err != nil {
+		t.Fatalf("opening xl/workbook.xml: %v", err)
+	}
+	defer f.Close()
+	workbook, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("reading xl/workbook.xml: %v", err)
+	}
+	if !strings.Contains(string(workbook), `<sheet name="Expenses"`) {
+		t.Errorf("workbook does not name the sheet Expenses: %s", workbook)
+	}
+}
+
+func TestCSVImportsBack(t *testing.T) {
+	expense := models.Expense{ID: 1, Description: "Kopi susu", Category: "Makanan", Amount: 25000.5, Date: time.Date(2026, 10, 19, 0, 30, 0, 0, jakarta)}
+	now := time.Date(2026, 10, 20, 12, 0, 0, 0, jakarta)
+
+	for _, lang := range i18n.Supported {
+		t.Run(string(lang), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteCSV(&buf, []models.Expense{expense}, i18n.NewPrinter(lang, jakarta)); err != nil {
+				t.Fatalf("WriteCSV: %v", err)
+			}
+
+			format, rows, err := importer.Parse(buf.Bytes(), now)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if format != "SmartExpenseAI" || len(rows) != 1 {
+				t.Fatalf("Parse = %s, %d rows, want SmartExpenseAI, 1 row", format, len(rows))
+			}
+			row := rows[0]
+			if row.Description != expense.Description || row.Category != expense.Category || row.Amount != expense.Amount ||
+				row.Date.Format("2006-01-02") != "2026-10-19" {
+				t.Errorf("imported %+v, want %+v", row, expense)
+			}
+		})
+	}
+}
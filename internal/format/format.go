@@ -0,0 +1,135 @@
+// Package format writes amounts and dates the way readers of a language expect them, such as
+// Rp1.250.000 and "19 Okt 2026" in Indonesian or Rp1,250,000 and "Oct 19, 2026" in English.
+package format
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Locale is how numbers, amounts and dates are written in one language
+type Locale struct {
+	// Thousands groups the digits of whole numbers, Decimal separates the cents
+	Thousands string
+	Decimal   string
+	// Currency is written before an amount, after the sign of a negative one
+	Currency    string
+	Months      [12]string
+	ShortMonths [12]string
+	// MonthFirst writes "Jan 2, 2006" instead of "2 Jan 2006"
+	MonthFirst bool
+	// Today, Yesterday and Tomorrow name the days next to the current one in relative dates
+	Today     string
+	Yesterday string
+	Tomorrow  string
+}
+
+var (
+	Indonesian = &Locale{
+		Thousands: ".",
+		Decimal:   ",",
+		Currency:  "Rp",
+		Months: [12]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+			"Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"},
+		Today:       "hari ini",
+		Yesterday:   "kemarin",
+		Tomorrow:    "besok",
+	}
+	English = &Locale{
+		Thousands: ",",
+		Decimal:   ".",
+		Currency:  "Rp",
+		Months: [12]string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		MonthFirst:  true,
+		Today:       "today",
+		Yesterday:   "yesterday",
+		Tomorrow:    "tomorrow",
+	}
+)
+
+// Money writes an amount with the currency symbol, such as Rp25.000, -Rp1.500 or Rp12.500,50.
+// Cents are rounded to two digits and only written when there are any.
+func (l *Locale) Money(amount float64) string {
+	number := l.decimal(amount)
+	if number[0] == '-' {
+		return "-" + l.Currency + number[1:]
+	}
+	return l.Currency + number
+}
+
+// decimal writes n with thousands separators, rounded to two decimals that are left out
+// when they are zero
+func (l *Locale) decimal(n float64) string {
+	cents := math.Round(math.Abs(n) * 100)
+	number := l.Integer(int64(cents / 100))
+	if fraction := int64(math.Mod(cents, 100)); fraction != 0 {
+		number += l.Decimal + fmt.Sprintf("%02d", fraction)
+	}
+	if n < 0 && cents != 0 {
+		return "-" + number
+	}
+	return number
+}
+
+// Integer writes a whole number with thousands separators
+func (l *Locale) Integer(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + l.Thousands + s[i:]
+	}
+	return sign + s
+}
+
+// Date writes a day, such as "2 Jan 2006"
+func (l *Locale) Date(t time.Time) string {
+	if l.MonthFirst {
+		return fmt.Sprintf("%s %d, %d", l.shortMonth(t), t.Day(), t.Year())
+	}
+	return fmt.Sprintf("%d %s %d", t.Day(), l.shortMonth(t), t.Year())
+}
+
+// DayMonth writes a day without the year, such as "2 Jan"
+func (l *Locale) DayMonth(t time.Time) string {
+	if l.MonthFirst {
+		return fmt.Sprintf("%s %d", l.shortMonth(t), t.Day())
+	}
+	return fmt.Sprintf("%d %s", t.Day(), l.shortMonth(t))
+}
+
+// Month writes a month, such as "Januari 2006"
+func (l *Locale) Month(t time.Time) string {
+	return fmt.Sprintf("%s %d", l.Months[t.Month()-1], t.Year())
+}
+
+// RelativeDate writes t relative to now: today, yesterday and tomorrow by name, other
+// days of the same year without the year. Both times are compared in the location of t.
+func (l *Locale) RelativeDate(t, now time.Time) string {
+	now = now.In(t.Location())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, t.Location())
+	switch {
+	case day.Equal(today):
+		return l.Today
+	case day.Equal(today.AddDate(0, 0, -1)):
+		return l.Yesterday
+	case day.Equal(today.AddDate(0, 0, 1)):
+		return l.Tomorrow
+	case t.Year() == now.Year():
+		return l.DayMonth(t)
+	default:
+		return l.Date(t)
+	}
+}
+
+func (l *Locale) shortMonth(t time.Time) string {
+	return l.ShortMonths[t.Month()-1]
+}
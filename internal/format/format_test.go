@@ -0,0 +1,120 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		amount float64
+		id     string
+		en     string
+	}{
+		{0, "Rp0", "Rp0"},
+		{25000, "Rp25.000", "Rp25,000"},
+		{999, "Rp999", "Rp999"},
+		{1000, "Rp1.000", "Rp1,000"},
+		{1250000, "Rp1.250.000", "Rp1,250,000"},
+		{1234567890, "Rp1.234.567.890", "Rp1,234,567,890"},
+		// Refunds and budget overruns are negative
+		{-1500, "-Rp1.500", "-Rp1,500"},
+		{-250000.5, "-Rp250.000,50", "-Rp250,000.50"},
+		// Cents are written only when there are any, rounded to two digits
+		{12500.5, "Rp12.500,50", "Rp12,500.50"},
+		{0.3, "Rp0,30", "Rp0.30"},
+		{0.1 + 0.2, "Rp0,30", "Rp0.30"},
+		{1234.567, "Rp1.234,57", "Rp1,234.57"},
+		{1.999, "Rp2", "Rp2"},
+		{999.996, "Rp1.000", "Rp1,000"},
+		{12.04, "Rp12,04", "Rp12.04"},
+		// An amount that rounds to zero has no sign
+		{-0.004, "Rp0", "Rp0"},
+	}
+
+	for _, tt := range tests {
+		if got := Indonesian.Money(tt.amount); got != tt.id {
+			t.Errorf("Indonesian.Money(%v) = %q, want %q", tt.amount, got, tt.id)
+		}
+		if got := English.Money(tt.amount); got != tt.en {
+			t.Errorf("English.Money(%v) = %q, want %q", tt.amount, got, tt.en)
+		}
+	}
+}
+
+func TestInteger(t *testing.T) {
+	tests := []struct {
+		n  int64
+		id string
+		en string
+	}{
+		{0, "0", "0"},
+		{100, "100", "100"},
+		{1000, "1.000", "1,000"},
+		{123456, "123.456", "123,456"},
+		{-1000000, "-1.000.000", "-1,000,000"},
+		{-999, "-999", "-999"},
+	}
+
+	for _, tt := range tests {
+		if got := Indonesian.Integer(tt.n); got != tt.id {
+			t.Errorf("Indonesian.Integer(%d) = %q, want %q", tt.n, got, tt.id)
+		}
+		if got := English.Integer(tt.n); got != tt.en {
+			t.Errorf("English.Integer(%d) = %q, want %q", tt.n, got, tt.en)
+		}
+	}
+}
+
+func TestDates(t *testing.T) {
+	day := time.Date(2026, 8, 5, 15, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale   *Locale
+		date     string
+		dayMonth string
+		month    string
+	}{
+		{Indonesian, "5 Agu 2026", "5 Agu", "Agustus 2026"},
+		{English, "Aug 5, 2026", "Aug 5", "August 2026"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.locale.Date(day); got != tt.date {
+			t.Errorf("Date = %q, want %q", got, tt.date)
+		}
+		if got := tt.locale.DayMonth(day); got != tt.dayMonth {
+			t.Errorf("DayMonth = %q, want %q", got, tt.dayMonth)
+		}
+		if got := tt.locale.Month(day); got != tt.month {
+			t.Errorf("Month = %q, want %q", got, tt.month)
+		}
+	}
+}
+
+func TestRelativeDate(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	// 20:00 UTC is already the next day in WIB
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		t  time.Time
+		id string
+		en string
+	}{
+		{time.Date(2026, 10, 19, 8, 0, 0, 0, wib), "hari ini", "today"},
+		{time.Date(2026, 10, 18, 23, 59, 0, 0, wib), "kemarin", "yesterday"},
+		{time.Date(2026, 10, 20, 0, 0, 0, 0, wib), "besok", "tomorrow"},
+		{time.Date(2026, 1, 2, 12, 0, 0, 0, wib), "2 Jan", "Jan 2"},
+		{time.Date(2025, 12, 31, 12, 0, 0, 0, wib), "31 Des 2025", "Dec 31, 2025"},
+	}
+
+	for _, tt := range tests {
+		if got := Indonesian.RelativeDate(tt.t, now); got != tt.id {
+			t.Errorf("Indonesian.RelativeDate(%v) = %q, want %q", tt.t, got, tt.id)
+		}
+		if got := English.RelativeDate(tt.t, now); got != tt.en {
+			t.Errorf("English.RelativeDate(%v) = %q, want %q", tt.t, got, tt.en)
+		}
+	}
+}
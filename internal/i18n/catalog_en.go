@@ -0,0 +1,343 @@
+package i18n
+
+// english is the catalog for users whose Telegram client is not in an Indonesian language
+var english = map[string]string{
+	"language.name":        "English",
+	"language.current":     "🌐 Language: %s\n\nSwitch language with:\n%s",
+	"language.set":         "✅ Language set to %s.",
+	"language.invalid":     "Unknown language. Use /bahasa id or /bahasa en.",
+	"language.save_failed": "Error saving your language. Please try again.",
+
+	"auth.denied":     "You are not authorized to use this bot.",
+	"common.never":    "never",
+	"common.more":     "… and %d more",
+	"command.unknown": "Unknown command. Use /bantuan to see the help.",
+
+	"welcome.intro": "🤖 Welcome to SmartExpenseAI!\n\n" +
+		"What you can do:\n" +
+		"• Send a plain message to record an expense\n" +
+		"• Send a bank statement CSV file to import expenses",
+	"help.intro": "🤖 SmartExpenseAI help:\n\n" +
+		"Recording expenses:\n" +
+		"• Send a message such as: \"nasi padang lunch 25000\" or \"bought a book 50k\"\n" +
+		"• Add a #tag while recording (for example: \"coffee 25000 #office\") to find it with /cari #office\n\n" +
+		"Available commands:",
+	"help.example": "(example: %s)",
+
+	"command.args.id":           "ID",
+	"command.lihat":             "Show your expenses, page with ◀/▶ and edit or delete with the buttons",
+	"command.minggu":            "Show a recap of the last 7 days by category",
+	"command.bulan":             "Show a recap of the last 30 days by month",
+	"command.hapus":             "Delete an expense",
+	"command.hapus.example":     "/hapus 5",
+	"command.update":            "Change the description, amount, category, date or account with buttons",
+	"command.update.example":    "/update 5",
+	"command.undo":              "Undo the last recorded, changed, deleted or imported expenses",
+	"command.batal":             "Stop the running conversation, such as editing an expense (it ends by itself after 10 minutes without a reply)",
+	"command.riwayat":           "Show the change history of an expense",
+	"command.riwayat.example":   "/riwayat 5",
+	"command.pulihkan":          "Restore a deleted expense",
+	"command.pulihkan.example":  "/pulihkan 5",
+	"command.tanya":             "Ask about your expenses, or just send the question",
+	"command.tanya.args":        "QUESTION",
+	"command.tanya.example":     "/tanya how much did I spend on coffee this month?",
+	"command.cari":              "Search expenses by description or merchant",
+	"command.cari.args":         "WORDS [kategori=NAME] [tag=NAME] [min=N] [max=N] [dari=YYYY-MM-DD] [sampai=YYYY-MM-DD]",
+	"command.cari.example":      "/cari coffee #office min=20000",
+	"command.harian":            "Send today's summary, or schedule it every day",
+	"command.harian.args":       "[HH:MM|off]",
+	"command.harian.example":    "/harian 21:00",
+	"command.anggaran":          "Set the daily budget for the summary (0 to remove it)",
+	"command.anggaran.args":     "AMOUNT",
+	"command.anggaran.example":  "/anggaran 100000",
+	"command.pengingat":         "Remind you when nothing was recorded for N days",
+	"command.pengingat.args":    "N|off",
+	"command.pengingat.example": "/pengingat 2",
+	"command.jadwal":            "Show the status of scheduled jobs",
+	"command.ekspor":            "Export expenses to CSV or Excel",
+	"command.ekspor.args":       "[csv|xlsx] [START] [END] [kategori=NAME]",
+	"command.ekspor.example":    "/ekspor xlsx 2025-11-01 2025-11-30",
+	"command.impor":             "How to import a bank statement from a CSV file",
+	"command.token":             "Create, list or revoke REST API tokens",
+	"command.token.args":        "[daftar|hapus]",
+	"command.dashboard":         "Get a login link for the web dashboard",
+	"command.bahasa":            "Show or change the language of the bot",
+	"command.bahasa.args":       "[id|en]",
+	"command.bahasa.example":    "/bahasa en",
+	"command.bantuan":           "Show this help",
+
+	"natural.help": "🤖 SmartExpenseAI help:\n\n" +
+		"Recording expenses:\n" +
+		"• Send a message such as: \"nasi padang lunch 25000\" or \"bought a book 50k\"\n\n" +
+		"Plain commands you can send (in Indonesian):\n" +
+		"• \"lihat pengeluaranku\" - Show your latest expenses\n" +
+		"• \"rekap bulan ini\" - Show a recap of the last 30 days\n" +
+		"• \"rekap minggu ini\" - Show a recap of the last 7 days\n" +
+		"• \"hapus pengeluaran 5\" - Delete the expense with that ID\n" +
+		"• \"ubah pengeluaran 5\" - Change an expense with buttons\n" +
+		"• \"anggaran harian 100rb\" - Set the daily budget\n" +
+		"• \"berapa total jajan kopi bulan ini?\" - Ask anything about your expenses\n" +
+		"• \"bantuan\" - Show this help\n\n" +
+		"Every command is also available with a slash, see /bantuan",
+	"natural.unknown":           "Unknown command. Use a command such as 'lihat pengeluaranku' or send a message to record a new expense.",
+	"natural.delete.missing_id": "Please give the ID of the expense to delete.\nExample: hapus pengeluaran 5",
+	"natural.update.missing":    "Give the ID of the expense to change.\nExample: ubah pengeluaran 5",
+	"natural.budget.missing":    "Give the daily budget amount.\nExample: anggaran harian 100rb",
+
+	"expense.parse_failed": "🤖 Hi! I'm SmartExpenseAI, an assistant that helps you record your expenses.\n\n" +
+		"You can send messages such as:\n" +
+		"• \"nasi padang lunch 25000\"\n" +
+		"• \"bought a book 50k\"\n\n" +
+		"For everything else, use the commands:\n" +
+		"• /lihat - Show your latest expenses\n" +
+		"• /bulan - Monthly recap\n" +
+		"• /hapus - Delete an expense",
+	"expense.not_recognized": "🤖 Couldn't find an expense in your message.\n\n" +
+		"Examples that work:\n" +
+		"• \"nasi padang lunch 25000\"\n" +
+		"• \"bought a book 50k\"\n\n" +
+		"For everything else, use the commands:\n" +
+		"• /lihat - Show your latest expenses\n" +
+		"• /bulan - Monthly recap\n" +
+		"• /hapus - Delete an expense",
+	"expense.saved":       "✅ Saved:\nCategory: %s\nAmount: %s\nDescription: %s",
+	"expense.merchant":    "Merchant: %s",
+	"expense.tags":        "Tags: %s",
+	"expense.save_failed": "Error saving your expense. Please try again.",
+	"expense.not_found":   "No expense found with ID %d.",
+	"expense.deleted":     "✅ Expense with ID %d deleted.\nUndo with /undo or restore it later with /pulihkan %d",
+	"expense.restored":    "♻️ Expense with ID %d restored:\n\nDescription: %s\nAmount: %s\nCategory: %s",
+
+	"duplicate.question":  "🤔 This looks like a duplicate of an expense you just recorded:\n\nID %d: %s - %s (%s, %s)\n\nKeep both?",
+	"duplicate.keep":      "✅ Keep both",
+	"duplicate.discard":   "❌ Don't save",
+	"duplicate.expired":   "This question has expired.",
+	"duplicate.discarded": "👍 OK, the duplicate expense was not saved.",
+	"duplicate.kept":      "👍 OK, both are saved.",
+
+	"list.empty":  "You have no recorded expenses yet.",
+	"list.title":  "📋 Your Expenses:",
+	"list.row":    "ID: %d\n   %s\n   %s\n   Category: %s\n   Date: %s",
+	"list.hint":   "Tap ✏️ to edit or 🗑 to delete an expense.",
+	"list.edit":   "✏️ Edit %s",
+	"list.delete": "🗑 Delete %s",
+	"list.newer":  "◀ Newer",
+	"list.older":  "Older ▶",
+	"list.back":   "◀ Back to the list",
+
+	"delete.question":   "🗑 Delete this expense?\n\nID %d, %s: %s - %s (%s)",
+	"delete.confirm":    "✅ Yes, delete",
+	"delete.cancel":     "◀ Cancel",
+	"delete.missing_id": "Please give the ID of the expense to delete.\nExample: /hapus 5",
+	"delete.invalid_id": "The expense ID must be a number.\nExample: /hapus 5",
+	"delete.failed":     "Error deleting the expense with ID %d.",
+
+	"update.missing_id": "Please give the ID of the expense to change.\nExample: /update 5",
+
+	"edit.title":              "Editing the expense",
+	"edit.field.description":  "Description",
+	"edit.field.amount":       "Amount",
+	"edit.field.category":     "Category",
+	"edit.field.date":         "Date",
+	"edit.field.account":      "Account",
+	"edit.menu":               "✏️ Edit expense ID %d:\n\nDescription: %s\nAmount: %s\nCategory: %s\nDate: %s\nAccount: %s\n\nChoose what to change:",
+	"edit.done":               "✅ Done",
+	"edit.back":               "◀ Back",
+	"edit.prompt.description": "Send the new description.\nNow: %s",
+	"edit.prompt.amount":      "Send the new amount (for example: 25000, 25rb or 1,5jt).\nNow: %s",
+	"edit.prompt.category":    "Send the new category.\nNow: %s",
+	"edit.prompt.date":        "Send the new date: YYYY-MM-DD, DD/MM/YYYY, DD/MM, \"today\" or \"yesterday\".\nNow: %s",
+	"edit.prompt.account":     "Send the account name (for example: BCA, GoPay, Cash), or - to clear it.",
+	"edit.prompt.current":     "Now: %s",
+	"edit.invalid.amount":     "Invalid amount. Send a number such as 25000, 25rb or 1,5jt.",
+	"edit.invalid.date":       "Invalid date. Send something like 2025-11-05, 5/11/2025, 5/11, \"today\" or \"yesterday\".",
+	"edit.invalid.empty":      "%s can't be empty.",
+	"edit.finished":           "✅ Finished editing expense ID %d:\n\nDescription: %s\nAmount: %s\nCategory: %s",
+	"edit.failed":             "Error changing the expense with ID %d.",
+	"edit.expired":            "The edit has ended. Start again with /update ID.",
+	"edit.ended":              "The edit has ended.",
+	"edit.closed":             "Edit finished.",
+
+	"dialog.title":         "The conversation",
+	"dialog.timeout":       "⌛ %s ended because there was no reply. Please start again.",
+	"dialog.failed":        "Something went wrong, %s was stopped. Please start again.",
+	"dialog.cancelled":     "❌ %s cancelled.",
+	"dialog.none":          "Nothing is running right now.",
+	"dialog.cancel_failed": "Error cancelling. Please try again.",
+
+	"undo.created":  "↩️ Recording undone, the expense was deleted:",
+	"undo.deleted":  "↩️ Deletion undone, the expense is back:",
+	"undo.restored": "↩️ Restore undone, the expense was deleted again:",
+	"undo.updated":  "↩️ Change undone, the expense is back to:",
+	"undo.nothing":  "There is nothing to undo.",
+	"undo.failed":   "Error undoing the last change.",
+
+	"history.empty":      "There is no change history yet for the expense with ID %d.",
+	"history.title":      "🕘 History of expense ID %d:",
+	"history.created":    "recorded: %s",
+	"history.updated":    "changed: %s → %s",
+	"history.deleted":    "deleted",
+	"history.restored":   "restored",
+	"history.undone":     "(undone %s)",
+	"history.missing_id": "Please give the expense ID.\nExample: /riwayat 5",
+	"history.failed":     "Error loading the expense history.",
+
+	"restore.missing_id": "Please give the ID of the expense to restore.\nExample: /pulihkan 5",
+	"restore.not_found":  "There is no deleted expense with ID %d.",
+	"restore.failed":     "Error restoring the expense with ID %d.",
+
+	"ask.missing_question": "Write your question after the command.\nExample: /tanya how much did I spend on coffee this month?",
+	"ask.not_understood":   "Sorry, I can't answer that yet. Try asking something like: \"how much did I spend on coffee this month?\" or \"when did I last pay for electricity?\"",
+	"ask.failed":           "Error answering the question. Please try again.",
+	"answer.none":          "🔎 No expenses match your question.",
+	"answer.count":         "💬 There are %d expenses, %s in total.",
+	"answer.average":       "💬 %s per expense on average (%d expenses, %s in total).",
+	"answer.max":           "💬 Largest expense: %s.",
+	"answer.min":           "💬 Smallest expense: %s.",
+	"answer.latest":        "💬 Last on %s.",
+	"answer.total":         "💬 %s in total from %d expenses.",
+	"answer.matches":       "Matching expenses:",
+	"answer.period":        "Period: %s - %s",
+	"answer.since":         "Since: %s",
+	"answer.until":         "Until: %s",
+	"answer.category":      "Category: %s",
+	"answer.keywords":      "Keywords: %s",
+
+	"search.invalid": "Wrong format. Use: /cari [keywords] [kategori=NAME] [tag=NAME] [min=N] [max=N] [dari=YYYY-MM-DD] [sampai=YYYY-MM-DD] [hal=N]\n" +
+		"Example: /cari coffee #office min=20000 dari=2025-11-01",
+	"search.failed":  "Error searching expenses. Please try again.",
+	"search.none":    "🔎 No expenses match your search.",
+	"search.no_page": "🔎 There is no page %d, the search has only %d pages.",
+	"search.found":   "🔎 %d expenses found:",
+	"search.page":    "Page %d/%d",
+	"search.next":    "Next page: %s hal=%d",
+
+	"recap.total":         "Total: %s",
+	"recap.weekly.empty":  "No expenses in the last 7 days.",
+	"recap.weekly.title":  "🧾 Weekly Recap:",
+	"recap.monthly.empty": "No expenses in the last 30 days.",
+	"recap.monthly.title": "🧾 30-Day Expense Recap:",
+	"recap.monthly.total": "Total for the last 30 days: %s",
+
+	"digest.title":        "📊 Daily Summary (%s):",
+	"digest.empty":        "No expenses recorded.",
+	"digest.budget":       "Daily budget: %s",
+	"digest.remaining":    "✅ Budget left: %s",
+	"digest.over":         "⚠️ Over budget by: %s",
+	"digest.set":          "✅ The daily summary will be sent every day at %s.",
+	"digest.off":          "✅ Daily summary turned off.",
+	"digest.invalid_time": "Wrong time format. Use HH:MM, for example: /harian 21:00",
+	"digest.save_failed":  "Error saving the daily summary setting.",
+
+	"budget.set":         "✅ Daily budget set to %s.",
+	"budget.cleared":     "✅ Daily budget removed.",
+	"budget.missing":     "Please give the daily budget amount.\nExample: /anggaran 100000",
+	"budget.invalid":     "The budget amount must be a number.\nExample: /anggaran 100000",
+	"budget.save_failed": "Error saving the daily budget.",
+
+	"reminder.never":       "⏰ You haven't recorded any expenses yet.\nSend a message such as \"lunch 25000\" to start.",
+	"reminder.inactive":    "⏰ You haven't recorded any expenses for %d days.\nDon't forget to record today's expenses!",
+	"reminder.set":         "✅ You'll be reminded when nothing is recorded for %d days.",
+	"reminder.off":         "✅ Recording reminder turned off.",
+	"reminder.invalid":     "The number of days must be a number.\nExample: /pengingat 2",
+	"reminder.save_failed": "Error saving the reminder setting.",
+
+	"import.help": "📥 Importing a bank statement:\n\n" +
+		"Send the exported statement CSV file to this chat. Supported formats: %s.\n\n" +
+		"You'll see a preview first (including the duplicates that are skipped) before anything is saved.",
+	"import.unreadable":             "❌ The file can't be read.\n\nSupported formats: %s (CSV).",
+	"import.empty":                  "No expenses found in this %s file.",
+	"import.title":                  "📥 Import Preview (%s):",
+	"import.new":                    "New: %d expenses, %s in total",
+	"import.duplicates":             "Duplicates (skipped): %d",
+	"import.duplicate_of":           "↳ already logged as #%d %s",
+	"import.question":               "Nothing has been saved yet. Import now?",
+	"import.confirm":                "✅ Import",
+	"import.cancel":                 "❌ Cancel",
+	"import.done":                   "✅ %d expenses imported from %s (%s in total).",
+	"import.cancelled":              "Import cancelled. No expenses were saved.",
+	"import.expired":                "The import preview has expired. Please send the file again.",
+	"import.save_failed":            "❌ Error saving the import. No expenses were saved.",
+	"import.not_csv":                "Send a bank statement in CSV format to import it.",
+	"import.too_large":              "The file is too large. The limit is 5 MB.",
+	"import.download_failed":        "Error downloading the file. Please try again.",
+	"import.duplicate_check_failed": "Error checking for duplicate expenses.",
+
+	"export.invalid": "Wrong format. Use: /ekspor [csv|xlsx] [START] [END] [kategori=NAME]\n" +
+		"START and END are plain dates in YYYY-MM-DD format.\n" +
+		"Example: /ekspor xlsx 2025-11-01 2025-11-30 kategori=Makanan",
+	"export.empty":              "No expenses match the export.",
+	"export.file_failed":        "Error creating the %s file.",
+	"export.caption":            "📤 %d expenses, %s in total",
+	"export.fetch_failed":       "Error loading the expenses to export.",
+	"export.file_name":          "expenses",
+	"export.sheet":              "Expenses",
+	"export.column.id":          "ID",
+	"export.column.date":        "Date",
+	"export.column.description": "Description",
+	"export.column.category":    "Category",
+	"export.column.amount":      "Amount",
+
+	"jobs.empty":   "No jobs are scheduled yet.",
+	"jobs.title":   "🗓️ Job Status:",
+	"jobs.row":     "• %s (%s)\n   Last run: %s\n   Next run: %s\n   Runs: %d",
+	"jobs.running": "Running",
+	"jobs.error":   "Last error: %s",
+	"jobs.failed":  "Error loading the job status.",
+
+	"token.created": "🔑 New API token:\n\n<code>%s</code>\n\n" +
+		"Keep this token somewhere safe, it is only shown once.\n" +
+		"Send it as a header: <code>Authorization: Bearer TOKEN</code>\n" +
+		"Revoke every token with /token hapus",
+	"token.none":          "You don't have an API token yet. Create one with /token",
+	"token.title":         "🔑 Active API tokens:",
+	"token.row":           "• %s… created %s, last used %s",
+	"token.usage":         "Use /token to create an API token, /token daftar to list the active tokens, or /token hapus to revoke them all.",
+	"token.create_failed": "Error creating the API token.",
+	"token.list_failed":   "Error loading the API tokens.",
+	"token.revoke_failed": "Error revoking the API tokens.",
+	"token.revoked":       "✅ %d API tokens revoked.",
+
+	"dashboard.link":           "🔐 Dashboard login link (valid for %d minutes, single use):\n\n%s",
+	"dashboard.not_configured": "The dashboard is not configured (PUBLIC_URL is not set).",
+	"dashboard.failed":         "Error creating the dashboard link.",
+
+	"web.title":                     "SmartExpenseAI Dashboard",
+	"web.login.title":               "Sign in to the Dashboard",
+	"web.login.telegram":            "Sign in with your Telegram account:",
+	"web.login.bot_before":          "Or send ",
+	"web.login.bot_after":           " to the bot to get a single-use login link.",
+	"web.login.invalid_link":        "The login link is invalid, expired or already used.",
+	"web.login.verify_failed":       "The Telegram login could not be verified.",
+	"web.login.not_allowed":         "This Telegram account is not allowed.",
+	"web.login.session_failed":      "Error creating the login session.",
+	"web.logout":                    "Sign out",
+	"web.filter.from":               "From",
+	"web.filter.to":                 "To",
+	"web.filter.category":           "Category",
+	"web.filter.all":                "All",
+	"web.filter.search":             "Search",
+	"web.filter.search_placeholder": "description",
+	"web.filter.apply":              "Apply",
+	"web.total":                     "Total",
+	"web.count":                     "%d expenses",
+	"web.by_category":               "By category",
+	"web.by_day":                    "By day",
+	"web.no_data":                   "No data.",
+	"web.save":                      "Save",
+	"web.delete":                    "Delete",
+	"web.delete_confirm":            "Delete this expense?",
+	"web.empty":                     "No expenses.",
+	"web.prev":                      "◀ Previous",
+	"web.next":                      "Next ▶",
+	"web.page":                      "Page %d of %d",
+	"web.error.csrf":                "Invalid CSRF token.",
+	"web.error.from":                "Invalid start date.",
+	"web.error.to":                  "Invalid end date.",
+	"web.error.load":                "Error loading the expenses.",
+	"web.error.not_found":           "Expense not found.",
+	"web.error.invalid_expense":     "A description, an amount above 0 and a date are required.",
+	"web.error.update":              "Error saving the expense.",
+	"web.error.delete":              "Error deleting the expense.",
+	"web.error.render":              "Error showing the page.",
+}
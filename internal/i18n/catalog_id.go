@@ -0,0 +1,343 @@
+package i18n
+
+// indonesian is the catalog of the default language; every other catalog has the same keys
+var indonesian = map[string]string{
+	"language.name":        "Bahasa Indonesia",
+	"language.current":     "🌐 Bahasa: %s\n\nGanti bahasa dengan:\n%s",
+	"language.set":         "✅ Bahasa diatur ke %s.",
+	"language.invalid":     "Bahasa tidak dikenal. Gunakan /bahasa id atau /bahasa en.",
+	"language.save_failed": "Gagal menyimpan bahasa. Silakan coba lagi.",
+
+	"auth.denied":     "Kamu tidak diizinkan menggunakan bot ini.",
+	"common.never":    "belum pernah",
+	"common.more":     "… dan %d lainnya",
+	"command.unknown": "Perintah tidak dikenali. Gunakan /bantuan untuk melihat bantuan.",
+
+	"welcome.intro": "🤖 Selamat datang di SmartExpenseAI!\n\n" +
+		"Fitur yang tersedia:\n" +
+		"• Kirim pesan biasa untuk mencatat pengeluaran\n" +
+		"• Kirim file CSV mutasi rekening untuk impor pengeluaran",
+	"help.intro": "🤖 Bantuan SmartExpenseAI:\n\n" +
+		"Cara mencatat pengeluaran:\n" +
+		"• Kirim pesan seperti: \"makan nasi padang 25000\" atau \"beli buku 50k\"\n" +
+		"• Tambahkan #tag saat mencatat (contoh: \"kopi 25000 #kantor\") untuk mencarinya dengan /cari #kantor\n\n" +
+		"Perintah yang tersedia:",
+	"help.example": "(contoh: %s)",
+
+	"command.args.id":           "ID",
+	"command.lihat":             "Lihat pengeluaranmu, geser halaman dengan ◀/▶ dan ubah atau hapus lewat tombol",
+	"command.minggu":            "Lihat rekap pengeluaran 7 hari terakhir per kategori",
+	"command.bulan":             "Lihat rekap pengeluaran 30 hari terakhir per bulan",
+	"command.hapus":             "Hapus pengeluaran",
+	"command.hapus.example":     "/hapus 5",
+	"command.update":            "Ubah deskripsi, jumlah, kategori, tanggal atau akun lewat tombol",
+	"command.update.example":    "/update 5",
+	"command.undo":              "Batalkan pencatatan, perubahan, penghapusan atau impor terakhir",
+	"command.batal":             "Hentikan proses tanya-jawab yang sedang berjalan, misalnya ubah pengeluaran (berakhir sendiri setelah 10 menit tanpa balasan)",
+	"command.riwayat":           "Lihat riwayat perubahan pengeluaran",
+	"command.riwayat.example":   "/riwayat 5",
+	"command.pulihkan":          "Pulihkan pengeluaran yang sudah dihapus",
+	"command.pulihkan.example":  "/pulihkan 5",
+	"command.tanya":             "Tanya tentang pengeluaranmu, atau langsung kirim pertanyaannya",
+	"command.tanya.args":        "PERTANYAAN",
+	"command.tanya.example":     "/tanya berapa total jajan kopi bulan ini?",
+	"command.cari":              "Cari pengeluaran berdasarkan deskripsi atau merchant",
+	"command.cari.args":         "KATA [kategori=NAMA] [tag=NAMA] [min=N] [max=N] [dari=YYYY-MM-DD] [sampai=YYYY-MM-DD]",
+	"command.cari.example":      "/cari kopi #kantor min=20000",
+	"command.harian":            "Kirim ringkasan hari ini, atau atur ringkasan otomatis setiap hari",
+	"command.harian.args":       "[HH:MM|off]",
+	"command.harian.example":    "/harian 21:00",
+	"command.anggaran":          "Atur anggaran harian untuk ringkasan (0 untuk menghapus)",
+	"command.anggaran.args":     "JUMLAH",
+	"command.anggaran.example":  "/anggaran 100000",
+	"command.pengingat":         "Ingatkan jika tidak mencatat selama N hari",
+	"command.pengingat.args":    "N|off",
+	"command.pengingat.example": "/pengingat 2",
+	"command.jadwal":            "Lihat status jadwal otomatis",
+	"command.ekspor":            "Ekspor pengeluaran ke CSV atau Excel",
+	"command.ekspor.args":       "[csv|xlsx] [AWAL] [AKHIR] [kategori=NAMA]",
+	"command.ekspor.example":    "/ekspor xlsx 2025-11-01 2025-11-30",
+	"command.impor":             "Cara impor mutasi rekening dari file CSV",
+	"command.token":             "Buat, lihat atau cabut token REST API",
+	"command.token.args":        "[daftar|hapus]",
+	"command.dashboard":         "Dapatkan link login dashboard web",
+	"command.bahasa":            "Lihat atau ganti bahasa bot",
+	"command.bahasa.args":       "[id|en]",
+	"command.bahasa.example":    "/bahasa en",
+	"command.bantuan":           "Tampilkan bantuan ini",
+
+	"natural.help": "🤖 Bantuan SmartExpenseAI:\n\n" +
+		"Cara mencatat pengeluaran:\n" +
+		"• Kirim pesan seperti: \"makan nasi padang 25000\" atau \"beli buku 50k\"\n\n" +
+		"Perintah alami yang bisa kamu gunakan:\n" +
+		"• \"lihat pengeluaranku\" - Lihat pengeluaran terakhir kamu\n" +
+		"• \"rekap bulan ini\" - Lihat rekap pengeluaran 30 hari terakhir\n" +
+		"• \"rekap minggu ini\" - Lihat rekap pengeluaran 7 hari terakhir\n" +
+		"• \"hapus pengeluaran 5\" - Hapus pengeluaran dengan ID tertentu\n" +
+		"• \"ubah pengeluaran 5\" - Ubah pengeluaran lewat tombol\n" +
+		"• \"anggaran harian 100rb\" - Atur anggaran harian\n" +
+		"• \"berapa total jajan kopi bulan ini?\" - Tanya apa saja tentang pengeluaranmu\n" +
+		"• \"bantuan\" - Tampilkan pesan bantuan ini\n\n" +
+		"Semua perintah juga tersedia dengan garis miring, lihat /bantuan",
+	"natural.unknown":           "Perintah tidak dikenali. Gunakan perintah seperti 'lihat pengeluaranku' atau kirim pesan untuk mencatat pengeluaran baru.",
+	"natural.delete.missing_id": "Silakan berikan ID pengeluaran yang ingin dihapus.\nContoh: hapus pengeluaran 5",
+	"natural.update.missing":    "Sebutkan ID pengeluaran yang ingin diubah.\nContoh: ubah pengeluaran 5",
+	"natural.budget.missing":    "Sebutkan jumlah anggaran harian.\nContoh: anggaran harian 100rb",
+
+	"expense.parse_failed": "🤖 Halo! Saya SmartExpenseAI, asisten yang membantu kamu mencatat pengeluaran.\n\n" +
+		"Kamu bisa kirim pesan seperti:\n" +
+		"• \"makan nasi padang 25000\"\n" +
+		"• \"beli buku 50k\"\n\n" +
+		"Untuk fitur lainnya, gunakan perintah:\n" +
+		"• /lihat - Lihat pengeluaran terakhir\n" +
+		"• /bulan - Rekap bulan ini\n" +
+		"• /hapus - Hapus pengeluaran",
+	"expense.not_recognized": "🤖 Tidak bisa mengenali pengeluaran dari pesanmu.\n\n" +
+		"Contoh format yang benar:\n" +
+		"• \"makan nasi padang 25000\"\n" +
+		"• \"beli buku 50k\"\n\n" +
+		"Untuk fitur lainnya, gunakan perintah:\n" +
+		"• /lihat - Lihat pengeluaran terakhir\n" +
+		"• /bulan - Rekap bulan ini\n" +
+		"• /hapus - Hapus pengeluaran",
+	"expense.saved":       "✅ Disimpan:\nKategori: %s\nJumlah: %s\nDeskripsi: %s",
+	"expense.merchant":    "Merchant: %s",
+	"expense.tags":        "Tag: %s",
+	"expense.save_failed": "Gagal menyimpan pengeluaranmu. Silakan coba lagi.",
+	"expense.not_found":   "Pengeluaran dengan ID %d tidak ditemukan.",
+	"expense.deleted":     "✅ Pengeluaran dengan ID %d berhasil dihapus.\nBatalkan dengan /undo atau pulihkan nanti dengan /pulihkan %d",
+	"expense.restored":    "♻️ Pengeluaran dengan ID %d dipulihkan:\n\nDeskripsi: %s\nJumlah: %s\nKategori: %s",
+
+	"duplicate.question":  "🤔 Sepertinya ini duplikat dari pengeluaran yang baru saja dicatat:\n\nID %d: %s - %s (%s, %s)\n\nSimpan keduanya?",
+	"duplicate.keep":      "✅ Simpan keduanya",
+	"duplicate.discard":   "❌ Jangan simpan",
+	"duplicate.expired":   "Pertanyaan ini sudah kedaluwarsa.",
+	"duplicate.discarded": "👍 Oke, pengeluaran duplikat tidak disimpan.",
+	"duplicate.kept":      "👍 Oke, keduanya disimpan.",
+
+	"list.empty":  "Kamu belum memiliki pengeluaran yang tercatat.",
+	"list.title":  "📋 Pengeluaran Kamu:",
+	"list.row":    "ID: %d\n   %s\n   %s\n   Kategori: %s\n   Tanggal: %s",
+	"list.hint":   "Tekan ✏️ untuk mengubah atau 🗑 untuk menghapus pengeluaran.",
+	"list.edit":   "✏️ Ubah %s",
+	"list.delete": "🗑 Hapus %s",
+	"list.newer":  "◀ Lebih baru",
+	"list.older":  "Lebih lama ▶",
+	"list.back":   "◀ Kembali ke daftar",
+
+	"delete.question":   "🗑 Hapus pengeluaran ini?\n\nID %d, %s: %s - %s (%s)",
+	"delete.confirm":    "✅ Ya, hapus",
+	"delete.cancel":     "◀ Batal",
+	"delete.missing_id": "Silakan berikan ID pengeluaran yang ingin dihapus.\nContoh: /hapus 5",
+	"delete.invalid_id": "ID pengeluaran harus berupa angka.\nContoh: /hapus 5",
+	"delete.failed":     "Gagal menghapus pengeluaran dengan ID %d.",
+
+	"update.missing_id": "Silakan berikan ID pengeluaran yang ingin diubah.\nContoh: /update 5",
+
+	"edit.title":              "Ubah pengeluaran",
+	"edit.field.description":  "Deskripsi",
+	"edit.field.amount":       "Jumlah",
+	"edit.field.category":     "Kategori",
+	"edit.field.date":         "Tanggal",
+	"edit.field.account":      "Akun",
+	"edit.menu":               "✏️ Ubah pengeluaran ID %d:\n\nDeskripsi: %s\nJumlah: %s\nKategori: %s\nTanggal: %s\nAkun: %s\n\nPilih yang ingin diubah:",
+	"edit.done":               "✅ Selesai",
+	"edit.back":               "◀ Kembali",
+	"edit.prompt.description": "Kirim deskripsi baru.\nSekarang: %s",
+	"edit.prompt.amount":      "Kirim jumlah baru (contoh: 25000, 25rb atau 1,5jt).\nSekarang: %s",
+	"edit.prompt.category":    "Kirim kategori baru.\nSekarang: %s",
+	"edit.prompt.date":        "Kirim tanggal baru: YYYY-MM-DD, DD/MM/YYYY, DD/MM, \"hari ini\" atau \"kemarin\".\nSekarang: %s",
+	"edit.prompt.account":     "Kirim nama akun (contoh: BCA, GoPay, Tunai), atau - untuk mengosongkan.",
+	"edit.prompt.current":     "Sekarang: %s",
+	"edit.invalid.amount":     "Jumlah tidak valid. Kirim angka seperti 25000, 25rb atau 1,5jt.",
+	"edit.invalid.date":       "Tanggal tidak valid. Kirim seperti 2025-11-05, 5/11/2025, 5/11, \"hari ini\" atau \"kemarin\".",
+	"edit.invalid.empty":      "%s tidak boleh kosong.",
+	"edit.finished":           "✅ Selesai mengubah pengeluaran ID %d:\n\nDeskripsi: %s\nJumlah: %s\nKategori: %s",
+	"edit.failed":             "Gagal mengubah pengeluaran dengan ID %d.",
+	"edit.expired":            "Sesi ubah sudah berakhir. Mulai lagi dengan /update ID.",
+	"edit.ended":              "Sesi ubah sudah berakhir.",
+	"edit.closed":             "Sesi ubah selesai.",
+
+	"dialog.title":         "Proses",
+	"dialog.timeout":       "⌛ %s sudah berakhir karena tidak ada balasan. Silakan mulai lagi.",
+	"dialog.failed":        "Terjadi kesalahan, %s dihentikan. Silakan mulai lagi.",
+	"dialog.cancelled":     "❌ %s dibatalkan.",
+	"dialog.none":          "Tidak ada proses yang sedang berjalan.",
+	"dialog.cancel_failed": "Gagal membatalkan. Silakan coba lagi.",
+
+	"undo.created":  "↩️ Pencatatan dibatalkan, pengeluaran dihapus:",
+	"undo.deleted":  "↩️ Penghapusan dibatalkan, pengeluaran dikembalikan:",
+	"undo.restored": "↩️ Pemulihan dibatalkan, pengeluaran dihapus lagi:",
+	"undo.updated":  "↩️ Perubahan dibatalkan, pengeluaran kembali menjadi:",
+	"undo.nothing":  "Tidak ada perubahan yang bisa dibatalkan.",
+	"undo.failed":   "Gagal membatalkan perubahan terakhir.",
+
+	"history.empty":      "Belum ada riwayat perubahan untuk pengeluaran dengan ID %d.",
+	"history.title":      "🕘 Riwayat pengeluaran ID %d:",
+	"history.created":    "dicatat: %s",
+	"history.updated":    "diubah: %s → %s",
+	"history.deleted":    "dihapus",
+	"history.restored":   "dipulihkan",
+	"history.undone":     "(dibatalkan %s)",
+	"history.missing_id": "Silakan berikan ID pengeluaran.\nContoh: /riwayat 5",
+	"history.failed":     "Gagal mengambil riwayat pengeluaran.",
+
+	"restore.missing_id": "Silakan berikan ID pengeluaran yang ingin dipulihkan.\nContoh: /pulihkan 5",
+	"restore.not_found":  "Tidak ada pengeluaran terhapus dengan ID %d.",
+	"restore.failed":     "Gagal memulihkan pengeluaran dengan ID %d.",
+
+	"ask.missing_question": "Tulis pertanyaanmu setelah perintah.\nContoh: /tanya berapa total jajan kopi bulan ini?",
+	"ask.not_understood":   "Maaf, pertanyaanmu belum bisa saya jawab. Coba tanyakan seperti: \"berapa total jajan kopi bulan ini?\" atau \"kapan terakhir bayar listrik?\"",
+	"ask.failed":           "Gagal menjawab pertanyaan. Silakan coba lagi.",
+	"answer.none":          "🔎 Tidak ada pengeluaran yang cocok dengan pertanyaanmu.",
+	"answer.count":         "💬 Ada %d pengeluaran, total %s.",
+	"answer.average":       "💬 Rata-rata %s per pengeluaran (%d pengeluaran, total %s).",
+	"answer.max":           "💬 Pengeluaran terbesar: %s.",
+	"answer.min":           "💬 Pengeluaran terkecil: %s.",
+	"answer.latest":        "💬 Terakhir pada %s.",
+	"answer.total":         "💬 Total %s dari %d pengeluaran.",
+	"answer.matches":       "Pengeluaran yang cocok:",
+	"answer.period":        "Periode: %s - %s",
+	"answer.since":         "Sejak: %s",
+	"answer.until":         "Sampai: %s",
+	"answer.category":      "Kategori: %s",
+	"answer.keywords":      "Kata kunci: %s",
+
+	"search.invalid": "Format salah. Gunakan: /cari [kata kunci] [kategori=NAMA] [tag=NAMA] [min=N] [max=N] [dari=YYYY-MM-DD] [sampai=YYYY-MM-DD] [hal=N]\n" +
+		"Contoh: /cari kopi #kantor min=20000 dari=2025-11-01",
+	"search.failed":  "Gagal mencari pengeluaran. Silakan coba lagi.",
+	"search.none":    "🔎 Tidak ada pengeluaran yang cocok dengan pencarianmu.",
+	"search.no_page": "🔎 Halaman %d tidak ada, hasil pencarian hanya %d halaman.",
+	"search.found":   "🔎 %d pengeluaran ditemukan:",
+	"search.page":    "Halaman %d/%d",
+	"search.next":    "Halaman berikutnya: %s hal=%d",
+
+	"recap.total":         "Total: %s",
+	"recap.weekly.empty":  "Tidak ada pengeluaran dalam 7 hari terakhir.",
+	"recap.weekly.title":  "🧾 Rekap Mingguan:",
+	"recap.monthly.empty": "Tidak ada pengeluaran dalam 30 hari terakhir.",
+	"recap.monthly.title": "🧾 Rekap Pengeluaran 30 Hari:",
+	"recap.monthly.total": "Total 30 Hari Terakhir: %s",
+
+	"digest.title":        "📊 Ringkasan Harian (%s):",
+	"digest.empty":        "Tidak ada pengeluaran yang tercatat.",
+	"digest.budget":       "Anggaran harian: %s",
+	"digest.remaining":    "✅ Sisa anggaran: %s",
+	"digest.over":         "⚠️ Melebihi anggaran: %s",
+	"digest.set":          "✅ Ringkasan harian akan dikirim setiap hari pukul %s.",
+	"digest.off":          "✅ Ringkasan harian dimatikan.",
+	"digest.invalid_time": "Format jam salah. Gunakan HH:MM, contoh: /harian 21:00",
+	"digest.save_failed":  "Gagal menyimpan pengaturan ringkasan harian.",
+
+	"budget.set":         "✅ Anggaran harian diatur ke %s.",
+	"budget.cleared":     "✅ Anggaran harian dihapus.",
+	"budget.missing":     "Silakan berikan jumlah anggaran harian.\nContoh: /anggaran 100000",
+	"budget.invalid":     "Jumlah anggaran harus berupa angka.\nContoh: /anggaran 100000",
+	"budget.save_failed": "Gagal menyimpan anggaran harian.",
+
+	"reminder.never":       "⏰ Kamu belum mencatat pengeluaran apa pun.\nKirim pesan seperti \"makan siang 25000\" untuk mulai mencatat.",
+	"reminder.inactive":    "⏰ Kamu belum mencatat pengeluaran selama %d hari.\nJangan lupa catat pengeluaranmu hari ini ya!",
+	"reminder.set":         "✅ Kamu akan diingatkan jika tidak mencatat pengeluaran selama %d hari.",
+	"reminder.off":         "✅ Pengingat pencatatan dimatikan.",
+	"reminder.invalid":     "Jumlah hari harus berupa angka.\nContoh: /pengingat 2",
+	"reminder.save_failed": "Gagal menyimpan pengaturan pengingat.",
+
+	"import.help": "📥 Impor mutasi rekening:\n\n" +
+		"Kirim file CSV hasil ekspor mutasi ke chat ini. Format yang didukung: %s.\n\n" +
+		"Kamu akan melihat pratinjau dulu (termasuk duplikat yang dilewati) sebelum data disimpan.",
+	"import.unreadable":             "❌ File tidak bisa dibaca.\n\nFormat yang didukung: %s (CSV).",
+	"import.empty":                  "Tidak ada pengeluaran yang ditemukan di file %s ini.",
+	"import.title":                  "📥 Pratinjau Impor (%s):",
+	"import.new":                    "Baru: %d pengeluaran, total %s",
+	"import.duplicates":             "Duplikat (dilewati): %d",
+	"import.duplicate_of":           "↳ sudah tercatat sebagai #%d %s",
+	"import.question":               "Belum ada yang disimpan. Lanjutkan impor?",
+	"import.confirm":                "✅ Impor",
+	"import.cancel":                 "❌ Batal",
+	"import.done":                   "✅ %d pengeluaran dari %s berhasil diimpor (total %s).",
+	"import.cancelled":              "Impor dibatalkan. Tidak ada pengeluaran yang disimpan.",
+	"import.expired":                "Pratinjau impor sudah kedaluwarsa. Silakan kirim ulang filenya.",
+	"import.save_failed":            "❌ Gagal menyimpan hasil impor. Tidak ada pengeluaran yang disimpan.",
+	"import.not_csv":                "Kirim file mutasi rekening dalam format CSV untuk diimpor.",
+	"import.too_large":              "File terlalu besar. Maksimal 5 MB.",
+	"import.download_failed":        "Gagal mengunduh file. Silakan coba lagi.",
+	"import.duplicate_check_failed": "Gagal memeriksa duplikat pengeluaran.",
+
+	"export.invalid": "Format salah. Gunakan: /ekspor [csv|xlsx] [AWAL] [AKHIR] [kategori=NAMA]\n" +
+		"AWAL dan AKHIR ditulis sebagai tanggal saja dengan format YYYY-MM-DD.\n" +
+		"Contoh: /ekspor xlsx 2025-11-01 2025-11-30 kategori=Makanan",
+	"export.empty":              "Tidak ada pengeluaran yang cocok untuk diekspor.",
+	"export.file_failed":        "Gagal membuat file %s.",
+	"export.caption":            "📤 %d pengeluaran, total %s",
+	"export.fetch_failed":       "Gagal mengambil data pengeluaran untuk diekspor.",
+	"export.file_name":          "pengeluaran",
+	"export.sheet":              "Pengeluaran",
+	"export.column.id":          "ID",
+	"export.column.date":        "Tanggal",
+	"export.column.description": "Deskripsi",
+	"export.column.category":    "Kategori",
+	"export.column.amount":      "Jumlah",
+
+	"jobs.empty":   "Belum ada jadwal yang terdaftar.",
+	"jobs.title":   "🗓️ Status Jadwal:",
+	"jobs.row":     "• %s (%s)\n   Terakhir: %s\n   Berikutnya: %s\n   Jumlah jalan: %d",
+	"jobs.running": "Sedang berjalan",
+	"jobs.error":   "Error terakhir: %s",
+	"jobs.failed":  "Gagal mengambil status jadwal.",
+
+	"token.created": "🔑 Token API baru:\n\n<code>%s</code>\n\n" +
+		"Simpan token ini, token hanya ditampilkan sekali.\n" +
+		"Gunakan sebagai header: <code>Authorization: Bearer TOKEN</code>\n" +
+		"Cabut semua token dengan /token hapus",
+	"token.none":          "Kamu belum punya token API. Buat dengan /token",
+	"token.title":         "🔑 Token API aktif:",
+	"token.row":           "• %s… dibuat %s, terakhir dipakai %s",
+	"token.usage":         "Gunakan /token untuk membuat token API, /token daftar untuk melihat token aktif, atau /token hapus untuk mencabut semuanya.",
+	"token.create_failed": "Gagal membuat token API.",
+	"token.list_failed":   "Gagal mengambil daftar token API.",
+	"token.revoke_failed": "Gagal mencabut token API.",
+	"token.revoked":       "✅ %d token API dicabut.",
+
+	"dashboard.link":           "🔐 Link login dashboard (berlaku %d menit, sekali pakai):\n\n%s",
+	"dashboard.not_configured": "Dashboard belum dikonfigurasi (PUBLIC_URL belum diatur).",
+	"dashboard.failed":         "Gagal membuat link dashboard.",
+
+	"web.title":                     "Dashboard SmartExpenseAI",
+	"web.login.title":               "Masuk ke Dashboard",
+	"web.login.telegram":            "Masuk dengan akun Telegram kamu:",
+	"web.login.bot_before":          "Atau kirim perintah ",
+	"web.login.bot_after":           " ke bot untuk mendapatkan link login sekali pakai.",
+	"web.login.invalid_link":        "Link login tidak valid, sudah kedaluwarsa, atau sudah dipakai.",
+	"web.login.verify_failed":       "Login Telegram gagal diverifikasi.",
+	"web.login.not_allowed":         "Akun Telegram ini tidak diizinkan.",
+	"web.login.session_failed":      "Gagal membuat sesi login.",
+	"web.logout":                    "Keluar",
+	"web.filter.from":               "Dari",
+	"web.filter.to":                 "Sampai",
+	"web.filter.category":           "Kategori",
+	"web.filter.all":                "Semua",
+	"web.filter.search":             "Cari",
+	"web.filter.search_placeholder": "deskripsi",
+	"web.filter.apply":              "Terapkan",
+	"web.total":                     "Total",
+	"web.count":                     "%d pengeluaran",
+	"web.by_category":               "Per kategori",
+	"web.by_day":                    "Per hari",
+	"web.no_data":                   "Tidak ada data.",
+	"web.save":                      "Simpan",
+	"web.delete":                    "Hapus",
+	"web.delete_confirm":            "Hapus pengeluaran ini?",
+	"web.empty":                     "Tidak ada pengeluaran.",
+	"web.prev":                      "◀ Sebelumnya",
+	"web.next":                      "Berikutnya ▶",
+	"web.page":                      "Halaman %d dari %d",
+	"web.error.csrf":                "Token CSRF tidak valid.",
+	"web.error.from":                "Tanggal awal tidak valid.",
+	"web.error.to":                  "Tanggal akhir tidak valid.",
+	"web.error.load":                "Gagal memuat pengeluaran.",
+	"web.error.not_found":           "Pengeluaran tidak ditemukan.",
+	"web.error.invalid_expense":     "Deskripsi, jumlah lebih dari 0, dan tanggal wajib diisi.",
+	"web.error.update":              "Gagal menyimpan pengeluaran.",
+	"web.error.delete":              "Gagal menghapus pengeluaran.",
+	"web.error.render":              "Gagal menampilkan halaman.",
+}
@@ -0,0 +1,43 @@
+package i18n
+
+import (
+	"time"
+
+	"SmartExpenseAI/internal/format"
+)
+
+// locales holds how each supported language writes numbers and dates
+var locales = map[Lang]*format.Locale{
+	Indonesian: format.Indonesian,
+	English:    format.English,
+}
+
+// Money writes an amount with the currency symbol, such as Rp25.000
+func (p *Printer) Money(amount float64) string {
+	return p.locale.Money(amount)
+}
+
+// Date writes a day, such as "2 Jan 2006"
+func (p *Printer) Date(t time.Time) string {
+	return p.locale.Date(t.In(p.location))
+}
+
+// DayMonth writes a day of the current year, such as "2 Jan"
+func (p *Printer) DayMonth(t time.Time) string {
+	return p.locale.DayMonth(t.In(p.location))
+}
+
+// Month writes a month, such as "Januari 2006"
+func (p *Printer) Month(t time.Time) string {
+	return p.locale.Month(t.In(p.location))
+}
+
+// RelativeDate writes a day relative to today, such as "kemarin" or "2 Jan"
+func (p *Printer) RelativeDate(t time.Time) string {
+	return p.locale.RelativeDate(t.In(p.location), time.Now())
+}
+
+// RelativeDateTime writes a day relative to today and the time of day, such as "kemarin 15:04"
+func (p *Printer) RelativeDateTime(t time.Time) string {
+	return p.RelativeDate(t) + " " + t.In(p.location).Format("15:04")
+}
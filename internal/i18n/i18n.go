@@ -0,0 +1,112 @@
+// Package i18n holds the message catalogs of the bot and formats messages, numbers and
+// dates in the language of a user.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"SmartExpenseAI/internal/format"
+)
+
+// Lang is a language the bot replies in, named by its ISO 639-1 code
+type Lang string
+
+const (
+	Indonesian Lang = "id"
+	English    Lang = "en"
+)
+
+// Default is the language used until a user's language is known
+const Default = Indonesian
+
+// Supported lists the languages the bot offers
+var Supported = []Lang{Indonesian, English}
+
+// catalogs holds the messages of each supported language by key
+var catalogs = map[Lang]map[string]string{
+	Indonesian: indonesian,
+	English:    english,
+}
+
+func init() {
+	// A key missing from a catalog would show up as the raw key in a reply
+	for _, lang := range Supported {
+		for key := range catalogs[Default] {
+			if _, ok := catalogs[lang][key]; !ok {
+				panic(fmt.Sprintf("i18n: %q catalog has no message %q", lang, key))
+			}
+		}
+	}
+}
+
+// Detect picks the language for the language code of a Telegram client, such as "en" or "id-ID".
+// Indonesian and the regional languages of Indonesia read Indonesian, other languages English.
+func Detect(code string) Lang {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(code)), "-")
+	switch base {
+	case "":
+		return Default
+	case "id", "in", "ms", "jv", "su":
+		return Indonesian
+	default:
+		return English
+	}
+}
+
+// Parse reads a language by its code or name, as typed after /bahasa
+func Parse(name string) (Lang, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "id", "indonesia", "indonesian", "bahasa indonesia":
+		return Indonesian, true
+	case "en", "english", "inggris", "bahasa inggris":
+		return English, true
+	default:
+		return "", false
+	}
+}
+
+// Printer writes messages, numbers and dates in one language
+type Printer struct {
+	lang     Lang
+	messages map[string]string
+	locale   *format.Locale
+	location *time.Location
+}
+
+// NewPrinter returns a printer for lang that shows times in loc. An unsupported language falls back to Default.
+func NewPrinter(lang Lang, loc *time.Location) *Printer {
+	messages, ok := catalogs[lang]
+	if !ok {
+		lang = Default
+		messages = catalogs[Default]
+	}
+	return &Printer{lang: lang, messages: messages, locale: locales[lang], location: loc}
+}
+
+// Lang returns the language of the printer
+func (p *Printer) Lang() Lang {
+	return p.lang
+}
+
+// Location returns the location the printer shows times in
+func (p *Printer) Location() *time.Location {
+	return p.location
+}
+
+// T returns the message with the given key, formatted with args as by fmt.Sprintf.
+// An empty key gives an empty string.
+func (p *Printer) T(key string, args ...any) string {
+	if key == "" {
+		return ""
+	}
+	message, ok := p.messages[key]
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
@@ -0,0 +1,36 @@
+package importer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// categoryRules maps keywords found in bank descriptions to a category
+var categoryRules = []struct {
+	category string
+	keywords []string
+}{
+	{"Makanan", []string{"gofood", "grabfood", "shopeefood", "resto", "restoran", "kopi", "coffee", "starbucks", "mcd", "kfc", "bakery", "warung", "makan"}},
+	{"Transport", []string{"gojek", "goride", "grab", "gocar", "maxim", "pertamina", "shell", "spbu", "bensin", "parkir", "tol", "krl", "mrt", "transjakarta", "kai"}},
+	{"Belanja", []string{"indomaret", "alfamart", "alfamidi", "superindo", "hypermart", "tokopedia", "shopee", "lazada", "blibli"}},
+	{"Tagihan", []string{"pln", "listrik", "pdam", "telkom", "indihome", "bpjs", "pulsa", "paket data", "internet", "asuransi"}},
+	{"Hiburan", []string{"netflix", "spotify", "youtube", "cinema", "xxi", "cgv", "steam"}},
+	{"Kesehatan", []string{"apotek", "kimia farma", "klinik", "rumah sakit", "dokter", "halodoc"}},
+	{"Pendidikan", []string{"gramedia", "buku", "kursus", "udemy", "sekolah", "kampus"}},
+}
+
+// CategorizeByRules returns a category for a description, or "" when no rule matches.
+// Keywords match whole words only, so that "kai" does not match "pakaian" nor "tol" "toko".
+func CategorizeByRules(description string) string {
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ") + " "
+	for _, rule := range categoryRules {
+		for _, keyword := range rule.keywords {
+			if strings.Contains(words, " "+keyword+" ") {
+				return rule.category
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package importer
+
+import "testing"
+
+func TestCategorizeByRules(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"QRIS GOFOOD JAKARTA", "Makanan"},
+		{"Kopi Kenangan", "Makanan"},
+		{"TRF GRAB*A-12345", "Transport"},
+		{"Tiket KAI Bandung", "Transport"},
+		{"Bayar tol Cipularang", "Transport"},
+		{"Paket Data Telkomsel", "Tagihan"},
+		{"Apotek Kimia Farma", "Kesehatan"},
+		// Keywords inside longer words do not match
+		{"Beli pakaian", ""},
+		{"Toko kelontong", ""},
+		{"Grabbag store", ""},
+		{"Shellfish market", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := CategorizeByRules(tt.description); got != tt.want {
+			t.Errorf("CategorizeByRules(%q) = %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package importer
+
+func init() {
+	// KlikBCA "Mutasi Rekening": amount carries a DB/CR suffix, dates have no year
+	Register(Mapping{
+		Name:               "BCA",
+		DateColumn:         "Tanggal Transaksi",
+		DateLayouts:        []string{"02/01/2006", "02/01/06", "02/01"},
+		DescriptionColumns: []string{"Keterangan"},
+		AmountColumn:       "Jumlah",
+	})
+
+	// Livin' by Mandiri / Mandiri Online: separate debit and credit columns
+	Register(Mapping{
+		Name:               "Mandiri",
+		DateColumn:         "Date",
+		DateLayouts:        []string{"02/01/06", "02/01/2006", "02 Jan 2006", "2006-01-02"},
+		DescriptionColumns: []string{"Description"},
+		DebitColumn:        "Debit",
+		CreditColumn:       "Credit",
+	})
+
+	// Jenius: signed amounts, outgoing money is negative, category provided by the app
+	Register(Mapping{
+		Name:               "Jenius",
+		DateColumn:         "Date",
+		DateLayouts:        []string{"02 Jan 2006", "2006-01-02", "02/01/2006"},
+		DescriptionColumns: []string{"Details", "Notes"},
+		AmountColumn:       "Amount",
+		NegativeIsExpense:  true,
+		CategoryColumn:     "Category",
+	})
+
+	// The bot's own /ekspor CSV, so exported files can be imported back. Its header is
+	// written in the language of the user, so each language has a mapping.
+	Register(Mapping{
+		Name:               "SmartExpenseAI",
+		DateColumn:         "Tanggal",
+		DateLayouts:        []string{"2006-01-02"},
+		DescriptionColumns: []string{"Deskripsi"},
+		AmountColumn:       "Jumlah",
+		CategoryColumn:     "Kategori",
+	})
+	Register(Mapping{
+		Name:               "SmartExpenseAI",
+		DateColumn:         "Date",
+		DateLayouts:        []string{"2006-01-02"},
+		DescriptionColumns: []string{"Description"},
+		AmountColumn:       "Amount",
+		CategoryColumn:     "Category",
+	})
+}
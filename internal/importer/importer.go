@@ -0,0 +1,312 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+// Row is a single expense read from a bank or e-wallet export
+type Row struct {
+	Date        time.Time
+	Description string
+	Amount      float64
+	Category    string
+	Duplicate   bool
+	// DuplicateOf is the stored expense a duplicate row repeats; it is only shown in the preview
+	DuplicateOf *models.Expense `json:"-"`
+}
+
+// Mapping describes how the columns of a bank export map onto an expense.
+// Column names are matched case-insensitively against the header row.
+type Mapping struct {
+	Name string
+
+	DateColumn  string
+	DateLayouts []string
+
+	// DescriptionColumns are joined with a space; only the first one is required
+	DescriptionColumns []string
+
+	// Either AmountColumn (signed, or suffixed with DB/CR) or DebitColumn/CreditColumn is used
+	AmountColumn string
+	DebitColumn  string
+	CreditColumn string
+
+	// NegativeIsExpense treats negative amounts as expenses (e-wallets) instead of positive ones
+	NegativeIsExpense bool
+
+	// CategoryColumn is optional; when empty the category is left for categorization
+	CategoryColumn string
+}
+
+// maxHeaderScan is how many leading lines may precede the header (account info, period, ...)
+const maxHeaderScan = 15
+
+var mappings []Mapping
+
+// Register adds a bank format; formats registered first win when several match
+func Register(mapping Mapping) {
+	mappings = append(mappings, mapping)
+}
+
+// Formats returns the names of the registered bank formats; a format with several mappings is named once
+func Formats() []string {
+	names := make([]string, 0, len(mappings))
+	for _, mapping := range mappings {
+		if !slices.Contains(names, mapping.Name) {
+			names = append(names, mapping.Name)
+		}
+	}
+	return names
+}
+
+// Parse detects the bank format of a CSV export and returns its expense rows.
+// Credits (incoming money) are skipped.
+func Parse(data []byte, now time.Time) (string, []Row, error) {
+	records, err := readRecords(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for i := 0; i < len(records) && i < maxHeaderScan; i++ {
+		for _, mapping := range mappings {
+			columns, ok := mapping.match(records[i])
+			if !ok {
+				continue
+			}
+
+			rows, err := mapping.parseRows(records[i+1:], columns, now)
+			if err != nil {
+				return mapping.Name, nil, err
+			}
+			return mapping.Name, rows, nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("unrecognized file format, supported formats: %s", strings.Join(Formats(), ", "))
+}
+
+// readRecords reads a CSV file using whichever of "," or ";" yields more columns
+func readRecords(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var best [][]string
+	bestWidth := 0
+	for _, comma := range []rune{',', ';', '\t'} {
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.Comma = comma
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+
+		records, err := reader.ReadAll()
+		if err != nil {
+			continue
+		}
+
+		width := 0
+		for _, record := range records {
+			if len(record) > width {
+				width = len(record)
+			}
+		}
+		if width > bestWidth {
+			best, bestWidth = records, width
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("file is not a valid CSV")
+	}
+	return best, nil
+}
+
+// match returns the index of every column the mapping needs, if the header has them all
+func (m Mapping) match(header []string) (map[string]int, bool) {
+	index := make(map[string]int)
+	for i, name := range header {
+		index[normalizeColumn(name)] = i
+	}
+
+	required := []string{m.DateColumn, m.DescriptionColumns[0]}
+	if m.AmountColumn != "" {
+		required = append(required, m.AmountColumn)
+	} else {
+		required = append(required, m.DebitColumn)
+	}
+
+	columns := make(map[string]int)
+	for _, name := range required {
+		i, ok := index[normalizeColumn(name)]
+		if !ok {
+			return nil, false
+		}
+		columns[name] = i
+	}
+
+	optional := append([]string{m.CreditColumn, m.CategoryColumn}, m.DescriptionColumns[1:]...)
+	for _, name := range optional {
+		if name == "" {
+			continue
+		}
+		if i, ok := index[normalizeColumn(name)]; ok {
+			columns[name] = i
+		}
+	}
+
+	return columns, true
+}
+
+func (m Mapping) parseRows(records [][]string, columns map[string]int, now time.Time) ([]Row, error) {
+	var rows []Row
+
+	cell := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(strings.Trim(record[i], "'"))
+	}
+
+	for line, record := range records {
+		dateValue := cell(record, m.DateColumn)
+		if dateValue == "" {
+			// Trailing summary lines (saldo awal, mutasi, ...) have no date
+			continue
+		}
+
+		date, err := parseDate(dateValue, m.DateLayouts, now)
+		if err != nil {
+			continue
+		}
+
+		var amount float64
+		isExpense := false
+		if m.AmountColumn != "" {
+			amount, isExpense, err = parseSignedAmount(cell(record, m.AmountColumn), m.NegativeIsExpense)
+		} else {
+			debit := cell(record, m.DebitColumn)
+			amount, err = parseAmount(debit)
+			isExpense = amount > 0
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+1, err)
+		}
+		if !isExpense || amount == 0 {
+			continue
+		}
+
+		var parts []string
+		for _, column := range m.DescriptionColumns {
+			if value := cell(record, column); value != "" {
+				parts = append(parts, value)
+			}
+		}
+
+		rows = append(rows, Row{
+			Date:        date,
+			Description: strings.Join(strings.Fields(strings.Join(parts, " ")), " "),
+			Amount:      amount,
+			Category:    cell(record, m.CategoryColumn),
+		})
+	}
+
+	return rows, nil
+}
+
+// parseDate tries each layout; layouts without a year get the most recent matching year
+func parseDate(value string, layouts []string, now time.Time) (time.Time, error) {
+	for _, layout := range layouts {
+		date, err := time.ParseInLocation(layout, value, now.Location())
+		if err != nil {
+			continue
+		}
+
+		if !strings.Contains(layout, "2006") && !strings.Contains(layout, "06") {
+			date = date.AddDate(now.Year(), 0, 0)
+			if date.After(now) {
+				date = date.AddDate(-1, 0, 0)
+			}
+		}
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+}
+
+// parseSignedAmount reads an amount such as "-25.000", "25,000.00 DB" or "25,000.00 CR"
+func parseSignedAmount(value string, negativeIsExpense bool) (float64, bool, error) {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+
+	switch {
+	case strings.HasSuffix(upper, "DB"):
+		amount, err := parseAmount(strings.TrimSuffix(upper, "DB"))
+		return amount, true, err
+	case strings.HasSuffix(upper, "CR"):
+		amount, err := parseAmount(strings.TrimSuffix(upper, "CR"))
+		return amount, false, err
+	}
+
+	amount, err := parseAmount(upper)
+	if err != nil {
+		return 0, false, err
+	}
+
+	negative := strings.HasPrefix(upper, "-") || strings.HasPrefix(upper, "(")
+	if negativeIsExpense {
+		return amount, negative, nil
+	}
+	return amount, !negative, nil
+}
+
+// parseAmount reads an unsigned rupiah amount in either "1.234.567,89" or "1,234,567.89" notation
+func parseAmount(value string) (float64, error) {
+	s := strings.TrimSpace(value)
+	s = strings.NewReplacer("RP", "", "Rp", "", "IDR", "", " ", "", "-", "", "+", "", "(", "", ")", "").Replace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	lastDot := strings.LastIndex(s, ".")
+	lastComma := strings.LastIndex(s, ",")
+
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		// Whichever separator comes last is the decimal separator
+		if lastComma > lastDot {
+			s = strings.ReplaceAll(s, ".", "")
+			s = strings.Replace(s, ",", ".", 1)
+		} else {
+			s = strings.ReplaceAll(s, ",", "")
+		}
+	case lastComma >= 0:
+		s = decimalOrGrouping(s, ",")
+	case lastDot >= 0:
+		s = decimalOrGrouping(s, ".")
+	}
+
+	amount, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// decimalOrGrouping treats a lone separator followed by exactly three digits as thousands grouping
+func decimalOrGrouping(s string, sep string) string {
+	parts := strings.Split(s, sep)
+	if len(parts) == 2 && len(parts[1]) != 3 {
+		return parts[0] + "." + parts[1]
+	}
+	return strings.Join(parts, "")
+}
+
+func normalizeColumn(name string) string {
+	name = strings.TrimPrefix(name, "\xef\xbb\xbf")
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
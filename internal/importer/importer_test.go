@@ -0,0 +1,177 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testNow is when the fixtures are imported; dates without a year are read relative to it
+var testNow = time.Date(2026, 10, 19, 12, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, testNow.Location())
+}
+
+func TestParseFixtures(t *testing.T) {
+	tests := []struct {
+		file   string
+		format string
+		want   []Row
+	}{
+		{"bca.csv", "BCA", []Row{
+			{Date: day(2026, 10, 5), Description: "TRSF E-BANKING DB 0510/FTSCY/WS95031 GOFOOD", Amount: 25000},
+			{Date: day(2026, 10, 13), Description: "BYR VIA E-BANKING PLN PRABAYAR", Amount: 1250500.5},
+			// 30/12 has not come yet this year, so it is last year's
+			{Date: day(2025, 12, 30), Description: "TARIKAN ATM 30/12", Amount: 100000},
+		}},
+		{"mandiri.csv", "Mandiri", []Row{
+			{Date: day(2026, 10, 1), Description: "Pembayaran Tokopedia", Amount: 150000},
+			{Date: day(2026, 10, 12), Description: "QRIS Kopi Kenangan", Amount: 27500.5},
+		}},
+		{"jenius.csv", "Jenius", []Row{
+			{Date: day(2026, 10, 5), Description: "Starbucks Senayan Kopi pagi", Amount: 55000, Category: "Food & Drinks"},
+			{Date: day(2026, 10, 7), Description: "Grab", Amount: 18500, Category: "Transportation"},
+		}},
+		{"smartexpenseai.csv", "SmartExpenseAI", []Row{
+			{Date: day(2026, 10, 19), Description: "Kopi susu", Amount: 25000, Category: "Makanan"},
+			{Date: day(2026, 10, 18), Description: "Parkir mall", Amount: 12500.5, Category: "Transport"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			format, rows, err := Parse(data, testNow)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("format = %q, want %q", format, tt.format)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d: %+v", len(rows), len(tt.want), rows)
+			}
+			for i, want := range tt.want {
+				got := rows[i]
+				if !got.Date.Equal(want.Date) || got.Description != want.Description || got.Amount != want.Amount || got.Category != want.Category {
+					t.Errorf("row %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "unknown.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Parse(data, testNow); err == nil {
+		t.Error("Parse of an unknown format succeeded")
+	}
+	if _, _, err := Parse(nil, testNow); err == nil {
+		t.Error("Parse of an empty file succeeded")
+	}
+}
+
+func TestParseInvalidAmount(t *testing.T) {
+	data := []byte("Tanggal,Deskripsi,Jumlah\n2026-10-19,Kopi,dua puluh\n")
+	if _, _, err := Parse(data, testNow); err == nil {
+		t.Error("Parse with an unreadable amount succeeded")
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		err   bool
+	}{
+		{"25000", 25000, false},
+		// Indonesian notation
+		{"1.234.567,89", 1234567.89, false},
+		{"25.000", 25000, false},
+		{"25,5", 25.5, false},
+		{"150.000,00", 150000, false},
+		// English notation
+		{"1,234,567.89", 1234567.89, false},
+		{"25,000", 25000, false},
+		{"25.50", 25.5, false},
+		{"1,000,000", 1000000, false},
+		// Currency and signs are ignored
+		{"Rp 15.000", 15000, false},
+		{"IDR 1,000.00", 1000, false},
+		{"-55,000.00", 55000, false},
+		{"(18,500.00)", 18500, false},
+		{"", 0, false},
+		{"dua puluh", 0, true},
+		{"1.2.3,4,5", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAmount(tt.value)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("parseAmount(%q) = %v, %v, want %v, error %v", tt.value, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestParseSignedAmount(t *testing.T) {
+	tests := []struct {
+		value             string
+		negativeIsExpense bool
+		amount            float64
+		expense           bool
+	}{
+		{"25,000.00 DB", false, 25000, true},
+		{"25,000.00 cr", false, 25000, false},
+		{"25000", false, 25000, true},
+		{"-25000", false, 25000, false},
+		{"-55,000.00", true, 55000, true},
+		{"(18.500)", true, 18500, true},
+		{"10,000,000.00", true, 10000000, false},
+	}
+
+	for _, tt := range tests {
+		amount, expense, err := parseSignedAmount(tt.value, tt.negativeIsExpense)
+		if err != nil || amount != tt.amount || expense != tt.expense {
+			t.Errorf("parseSignedAmount(%q, %v) = %v, %v, %v, want %v, %v", tt.value, tt.negativeIsExpense, amount, expense, err, tt.amount, tt.expense)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	dayFirst := []string{"02/01/2006", "02/01/06", "02/01"}
+
+	tests := []struct {
+		value   string
+		layouts []string
+		want    time.Time
+		err     bool
+	}{
+		// Day first, never month first
+		{"05/10/2026", dayFirst, day(2026, 10, 5), false},
+		{"13/10/2026", dayFirst, day(2026, 10, 13), false},
+		{"10/13/2026", dayFirst, time.Time{}, true},
+		{"05/10/26", dayFirst, day(2026, 10, 5), false},
+		// Without a year: this year unless that is still to come
+		{"19/10", dayFirst, day(2026, 10, 19), false},
+		{"20/10", dayFirst, day(2025, 10, 20), false},
+		{"2026-10-05", []string{"2006-01-02"}, day(2026, 10, 5), false},
+		{"05 Oct 2026", []string{"02 Jan 2006"}, day(2026, 10, 5), false},
+		{"05-10-2026", dayFirst, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDate(tt.value, tt.layouts, testNow)
+		if (err != nil) != tt.err || !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %v, %v, want %v, error %v", tt.value, got, err, tt.want, tt.err)
+		}
+	}
+}
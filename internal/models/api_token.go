@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIToken authenticates REST API requests on behalf of a user; only the SHA-256 hash is stored
+type APIToken struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	UserID     uint           `json:"user_id" gorm:"not null;index"`
+	Prefix     string         `json:"prefix"`
+	TokenHash  string         `json:"-" gorm:"not null;uniqueIndex"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Actions recorded in the expense audit log
+const (
+	ExpenseCreated  = "create"
+	ExpenseUpdated  = "update"
+	ExpenseDeleted  = "delete"
+	ExpenseRestored = "restore"
+)
+
+// ExpenseSnapshot holds the editable fields of an expense at one point in time
+type ExpenseSnapshot struct {
+	Description string    `json:"description"`
+	Category    string    `json:"category"`
+	Merchant    string    `json:"merchant,omitempty"`
+	Account     string    `json:"account,omitempty"`
+	Tags        Tags      `json:"tags,omitempty"`
+	Amount      float64   `json:"amount"`
+	Date        time.Time `json:"date"`
+}
+
+// SnapshotOf captures the editable fields of an expense
+func SnapshotOf(expense *Expense) *ExpenseSnapshot {
+	return &ExpenseSnapshot{
+		Description: expense.Description,
+		Category:    expense.Category,
+		Merchant:    expense.Merchant,
+		Account:     expense.Account,
+		Tags:        expense.Tags,
+		Amount:      expense.Amount,
+		Date:        expense.Date,
+	}
+}
+
+// ExpenseEvent is one entry of the audit log. Events written by the same action,
+// such as the rows of an import, share a Batch and are undone together.
+type ExpenseEvent struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	UserID    uint             `json:"user_id" gorm:"not null;index"`
+	ExpenseID uint             `json:"expense_id" gorm:"not null;index"`
+	Batch     string           `json:"batch" gorm:"not null"`
+	Action    string           `json:"action" gorm:"not null"`
+	Before    *ExpenseSnapshot `json:"before" gorm:"serializer:json"`
+	After     *ExpenseSnapshot `json:"after" gorm:"serializer:json"`
+	UndoneAt  *time.Time       `json:"undone_at"`
+	CreatedAt time.Time        `json:"created_at"`
+}
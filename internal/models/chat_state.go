@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ChatState is the step of a multi-step conversation a chat is in, such as a guided edit.
+// Data holds the values collected so far as JSON.
+type ChatState struct {
+	ChatID    int64     `json:"chat_id" gorm:"primaryKey;autoIncrement:false"`
+	Flow      string    `json:"flow" gorm:"not null"`
+	Step      string    `json:"step" gorm:"not null"`
+	Data      string    `json:"data" gorm:"not null;default:''"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
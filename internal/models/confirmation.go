@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// PendingConfirmation is an action waiting for the user to confirm it with a button, such as a
+// previewed import or a likely duplicate expense. Data holds what is needed to carry it out as JSON.
+type PendingConfirmation struct {
+	Key       string    `json:"key" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	Data      string    `json:"data" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
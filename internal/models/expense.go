@@ -11,6 +11,9 @@ type Expense struct {
 	UserID      uint           `json:"user_id" gorm:"not null"`
 	Description string         `json:"description"`
 	Category    string         `json:"category"`
+	Merchant    string         `json:"merchant" gorm:"not null;default:''"`
+	Account     string         `json:"account" gorm:"not null;default:''"`
+	Tags        Tags           `json:"tags" gorm:"not null;default:''"`
 	Amount      float64        `json:"amount"`
 	Date        time.Time      `json:"date"`
 	CreatedAt   time.Time      `json:"created_at"`
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type ScheduledJob struct {
+	Name        string     `json:"name" gorm:"primaryKey"`
+	Schedule    string     `json:"schedule"`
+	LastRunAt   *time.Time `json:"last_run_at"`
+	LastError   string     `json:"last_error"`
+	RunCount    int        `json:"run_count"`
+	LockedBy    string     `json:"locked_by"`
+	LockedUntil *time.Time `json:"locked_until"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
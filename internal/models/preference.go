@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserPreference struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	UserID         uint           `json:"user_id" gorm:"not null;uniqueIndex"`
+	DailyBudget    float64        `json:"daily_budget"`
+	DigestEnabled  bool           `json:"digest_enabled"`
+	DigestTime     string         `json:"digest_time"`
+	ReminderDays   int            `json:"reminder_days"`
+	LastReminderAt *time.Time     `json:"last_reminder_at"`
+	Language       string         `json:"language"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
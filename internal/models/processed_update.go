@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// ProcessedUpdate records a Telegram update_id that was already handled, so retries are ignored
+type ProcessedUpdate struct {
+	UpdateID  int       `json:"update_id" gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
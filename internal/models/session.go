@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// LoginToken is a one-time dashboard login link sent by the bot; only the hash is stored
+type LoginToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// WebSession is a logged-in dashboard browser session
+type WebSession struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	TokenHash string    `json:"-" gorm:"not null;uniqueIndex"`
+	CSRFToken string    `json:"-" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Tags are the labels of an expense. They are stored as one text column in the form ",kopi,kantor,"
+// so that a single tag can be matched with LIKE '%,kopi,%' on every database.
+type Tags []string
+
+// NormalizeTag lowercases a tag and strips a leading #; commas are not allowed inside a tag
+func NormalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	tag = strings.TrimLeft(tag, "#")
+	return strings.ReplaceAll(tag, ",", "")
+}
+
+// NewTags normalizes the given tags and drops empty and repeated ones
+func NewTags(values []string) Tags {
+	var tags Tags
+	seen := make(map[string]bool)
+	for _, value := range values {
+		tag := NormalizeTag(value)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// HashTags returns the #words of a message as tags
+func HashTags(text string) Tags {
+	var values []string
+	for _, word := range strings.Fields(text) {
+		if strings.HasPrefix(word, "#") {
+			values = append(values, strings.TrimRight(word, ".,!?"))
+		}
+	}
+	return NewTags(values)
+}
+
+// Contains reports whether the tag, normalized, is one of the tags
+func (t Tags) Contains(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, existing := range t {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Tags) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return "", nil
+	}
+	return "," + strings.Join(t, ",") + ",", nil
+}
+
+func (t *Tags) Scan(value interface{}) error {
+	var text string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Tags", value)
+	}
+
+	*t = nil
+	for _, tag := range strings.Split(text, ",") {
+		if tag != "" {
+			*t = append(*t, tag)
+		}
+	}
+	return nil
+}
+
+// GormDataType stores tags as text
+func (Tags) GormDataType() string {
+	return "text"
+}
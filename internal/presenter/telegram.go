@@ -0,0 +1,832 @@
+package presenter
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"SmartExpenseAI/internal/i18n"
+	"SmartExpenseAI/internal/importer"
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+	"SmartExpenseAI/internal/services"
+)
+
+// importPreviewRows is how many rows are listed in the dry-run preview
+const importPreviewRows = 10
+
+// Callback data prefixes of the duplicate confirmation buttons, followed by the pending token
+const (
+	DuplicateKeepCallback    = "dup:keep:"
+	DuplicateDiscardCallback = "dup:drop:"
+)
+
+// Callback data of the import preview buttons
+const (
+	ImportConfirmCallback = "import:confirm"
+	ImportCancelCallback  = "import:cancel"
+)
+
+// Callback data prefixes of the /lihat buttons. The page buttons are followed by the cursor
+// of the first or last expense shown, the expense buttons by the expense ID.
+const (
+	ListOlderCallback            = "list:old:"
+	ListNewerCallback            = "list:new:"
+	ExpenseEditCallback          = "exp:edit:"
+	ExpenseDeleteCallback        = "exp:del:"
+	ExpenseDeleteConfirmCallback = "exp:delok:"
+)
+
+// Callback data of the guided edit buttons; the field button is followed by the field name
+const (
+	EditFieldCallback = "edit:field:"
+	EditBackCallback  = "edit:back"
+	EditDoneCallback  = "edit:done"
+)
+
+// editFieldLabels are the catalog keys naming the fields of the guided edit
+var editFieldLabels = map[string]string{
+	services.EditDescription: "edit.field.description",
+	services.EditAmount:      "edit.field.amount",
+	services.EditCategory:    "edit.field.category",
+	services.EditDate:        "edit.field.date",
+	services.EditAccount:     "edit.field.account",
+}
+
+// Languages tells the presenter which language each chat reads
+type Languages interface {
+	Language(chatID int64) i18n.Lang
+}
+
+// Telegram renders service results as Telegram messages
+type Telegram struct {
+	bot       *tgbotapi.BotAPI
+	location  *time.Location
+	languages Languages
+}
+
+func NewTelegram(bot *tgbotapi.BotAPI, loc *time.Location) *Telegram {
+	return &Telegram{bot: bot, location: loc}
+}
+
+// SetLanguages sets where the language of each chat comes from. Until it is set every
+// chat reads i18n.Default.
+func (t *Telegram) SetLanguages(languages Languages) {
+	t.languages = languages
+}
+
+// Printer returns the printer for the language of a chat
+func (t *Telegram) Printer(chatID int64) *i18n.Printer {
+	lang := i18n.Default
+	if t.languages != nil {
+		lang = t.languages.Language(chatID)
+	}
+	return i18n.NewPrinter(lang, t.location)
+}
+
+// Text sends a plain text message
+func (t *Telegram) Text(chatID int64, text string) {
+	t.send(tgbotapi.NewMessage(chatID, text))
+}
+
+// HTML sends a message formatted with Telegram's HTML; values interpolated into it must be
+// escaped with html.EscapeString
+func (t *Telegram) HTML(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	t.send(msg)
+}
+
+// EditText replaces the text of a message sent earlier, removing its buttons
+func (t *Telegram) EditText(chatID int64, messageID int, text string) {
+	t.send(tgbotapi.NewEditMessageText(chatID, messageID, text))
+}
+
+// Message sends the catalog message with the given key in the language of the chat
+func (t *Telegram) Message(chatID int64, key string, args ...any) {
+	t.Text(chatID, t.Printer(chatID).T(key, args...))
+}
+
+// EditMessage replaces a message sent earlier with the catalog message with the given key
+func (t *Telegram) EditMessage(chatID int64, messageID int, key string, args ...any) {
+	t.EditText(chatID, messageID, t.Printer(chatID).T(key, args...))
+}
+
+// ExpenseSaved confirms a saved expense
+func (t *Telegram) ExpenseSaved(chatID int64, expense *models.Expense) {
+	p := t.Printer(chatID)
+	responseText := p.T("expense.saved", expense.Category, p.Money(expense.Amount), expense.Description)
+	if expense.Merchant != "" {
+		responseText += "\n" + p.T("expense.merchant", expense.Merchant)
+	}
+	if len(expense.Tags) > 0 {
+		responseText += "\n" + p.T("expense.tags", "#"+strings.Join(expense.Tags, " #"))
+	}
+
+	sendResult, err := t.bot.Send(tgbotapi.NewMessage(chatID, responseText))
+	if err != nil {
+		log.Printf("Error sending message to user: %v", err)
+	} else {
+		log.Printf("Message sent successfully: %+v", sendResult)
+	}
+}
+
+// DuplicateQuestion asks whether to keep an expense that looks like a duplicate
+func (t *Telegram) DuplicateQuestion(chatID int64, duplicate *models.Expense, token string) {
+	p := t.Printer(chatID)
+	questionText := p.T("duplicate.question",
+		duplicate.ID,
+		duplicate.Description,
+		p.Money(duplicate.Amount),
+		duplicate.Category,
+		duplicate.CreatedAt.In(t.location).Format("15:04"))
+
+	msg := tgbotapi.NewMessage(chatID, questionText)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.T("duplicate.keep"), DuplicateKeepCallback+token),
+			tgbotapi.NewInlineKeyboardButtonData(p.T("duplicate.discard"), DuplicateDiscardCallback+token),
+		),
+	)
+	t.send(msg)
+}
+
+// ExpenseList shows a page of expenses with buttons to page through them and to change each one
+func (t *Telegram) ExpenseList(chatID int64, page *services.ExpensePage) {
+	p := t.Printer(chatID)
+	if len(page.Expenses) == 0 {
+		t.Text(chatID, p.T("list.empty"))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, expenseListText(p, page))
+	msg.ReplyMarkup = expenseListKeyboard(p, page)
+	t.send(msg)
+}
+
+// EditExpenseList replaces an earlier /lihat message with another page
+func (t *Telegram) EditExpenseList(chatID int64, messageID int, page *services.ExpensePage) {
+	p := t.Printer(chatID)
+	if len(page.Expenses) == 0 {
+		t.EditText(chatID, messageID, p.T("list.empty"))
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, expenseListText(p, page))
+	keyboard := expenseListKeyboard(p, page)
+	edit.ReplyMarkup = &keyboard
+	t.send(edit)
+}
+
+func expenseListText(p *i18n.Printer, page *services.ExpensePage) string {
+	listText := p.T("list.title") + "\n\n"
+	for _, expense := range page.Expenses {
+		listText += p.T("list.row",
+			expense.ID,
+			expense.Description,
+			p.Money(expense.Amount),
+			expense.Category,
+			p.RelativeDate(expense.Date)) + "\n\n"
+	}
+	listText += p.T("list.hint")
+	return listText
+}
+
+// expenseListKeyboard has an edit and a delete button per expense and the page buttons below
+func expenseListKeyboard(p *i18n.Printer, page *services.ExpensePage) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, expense := range page.Expenses {
+		id := strconv.FormatUint(uint64(expense.ID), 10)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.T("list.edit", id), ExpenseEditCallback+id),
+			tgbotapi.NewInlineKeyboardButtonData(p.T("list.delete", id), ExpenseDeleteCallback+id),
+		))
+	}
+
+	var navigation []tgbotapi.InlineKeyboardButton
+	if page.HasNewer {
+		navigation = append(navigation, tgbotapi.NewInlineKeyboardButtonData(p.T("list.newer"), ListNewerCallback+listCursor(page.Expenses[0])))
+	}
+	if page.HasOlder {
+		navigation = append(navigation, tgbotapi.NewInlineKeyboardButtonData(p.T("list.older"), ListOlderCallback+listCursor(page.Expenses[len(page.Expenses)-1])))
+	}
+	if len(navigation) > 0 {
+		rows = append(rows, navigation)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// listCursor encodes the position of an expense for the page buttons, within the 64 bytes of callback data
+func listCursor(expense models.Expense) string {
+	return fmt.Sprintf("%d:%d", expense.Date.UnixNano(), expense.ID)
+}
+
+// ParseListCursor decodes the cursor of a page button; an empty cursor means the latest page
+func ParseListCursor(data string) (*repository.ExpenseCursor, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	nanos, id, ok := strings.Cut(data, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid list cursor %q", data)
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid list cursor %q: %w", data, err)
+	}
+	expenseID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid list cursor %q: %w", data, err)
+	}
+	return &repository.ExpenseCursor{Date: time.Unix(0, unixNano), ID: uint(expenseID)}, nil
+}
+
+// DeleteQuestion asks in place of the /lihat message whether to delete an expense
+func (t *Telegram) DeleteQuestion(chatID int64, messageID int, expense *models.Expense) {
+	p := t.Printer(chatID)
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, deleteQuestionText(p, expense))
+	keyboard := deleteQuestionKeyboard(p, expense.ID)
+	edit.ReplyMarkup = &keyboard
+	t.send(edit)
+}
+
+// ConfirmDelete asks in a new message whether to delete an expense named in plain words
+func (t *Telegram) ConfirmDelete(chatID int64, expense *models.Expense) {
+	p := t.Printer(chatID)
+	msg := tgbotapi.NewMessage(chatID, deleteQuestionText(p, expense))
+	msg.ReplyMarkup = deleteQuestionKeyboard(p, expense.ID)
+	t.send(msg)
+}
+
+func deleteQuestionText(p *i18n.Printer, expense *models.Expense) string {
+	return p.T("delete.question", expense.ID, p.Date(expense.Date), expense.Description, p.Money(expense.Amount), expense.Category)
+}
+
+func deleteQuestionKeyboard(p *i18n.Printer, expenseID uint) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(p.T("delete.confirm"), ExpenseDeleteConfirmCallback+strconv.FormatUint(uint64(expenseID), 10)),
+			tgbotapi.NewInlineKeyboardButtonData(p.T("delete.cancel"), ListOlderCallback),
+		),
+	)
+}
+
+// ExpenseDeletedFromList confirms a deletion made from /lihat with a button back to the list
+func (t *Telegram) ExpenseDeletedFromList(chatID int64, messageID int, expenseID uint) {
+	p := t.Printer(chatID)
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, p.T("expense.deleted", expenseID, expenseID))
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(p.T("list.back"), ListOlderCallback)),
+	)
+	edit.ReplyMarkup = &keyboard
+	t.send(edit)
+}
+
+// EditMenu shows an expense with a button per field to change it
+func (t *Telegram) EditMenu(chatID int64, expense *models.Expense) {
+	p := t.Printer(chatID)
+	msg := tgbotapi.NewMessage(chatID, editMenuText(p, expense))
+	msg.ReplyMarkup = editMenuKeyboard(p)
+	t.send(msg)
+}
+
+// EditMenuInPlace shows the field buttons again in place of a value prompt
+func (t *Telegram) EditMenuInPlace(chatID int64, messageID int, expense *models.Expense) {
+	p := t.Printer(chatID)
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, editMenuText(p, expense))
+	keyboard := editMenuKeyboard(p)
+	edit.ReplyMarkup = &keyboard
+	t.send(edit)
+}
+
+func editMenuText(p *i18n.Printer, expense *models.Expense) string {
+	account := expense.Account
+	if account == "" {
+		account = "-"
+	}
+	return p.T("edit.menu", expense.ID, expense.Description, p.Money(expense.Amount), expense.Category, p.Date(expense.Date), account)
+}
+
+func editMenuKeyboard(p *i18n.Printer) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, field := range services.EditFields {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(p.T(editFieldLabels[field]), EditFieldCallback+field))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(p.T("edit.done"), EditDoneCallback))
+	rows = append(rows, row)
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// EditValuePrompt asks for the new value of a field in place of the edit menu
+func (t *Telegram) EditValuePrompt(chatID int64, messageID int, field string, expense *models.Expense) {
+	p := t.Printer(chatID)
+	var promptText string
+	switch field {
+	case services.EditDescription:
+		promptText = p.T("edit.prompt.description", expense.Description)
+	case services.EditAmount:
+		promptText = p.T("edit.prompt.amount", p.Money(expense.Amount))
+	case services.EditCategory:
+		promptText = p.T("edit.prompt.category", expense.Category)
+	case services.EditDate:
+		promptText = p.T("edit.prompt.date", p.Date(expense.Date))
+	case services.EditAccount:
+		promptText = p.T("edit.prompt.account")
+		if expense.Account != "" {
+			promptText += "\n" + p.T("edit.prompt.current", expense.Account)
+		}
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("✏️ ID %d - %s\n\n%s", expense.ID, p.T(editFieldLabels[field]), promptText))
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(p.T("edit.back"), EditBackCallback)),
+	)
+	edit.ReplyMarkup = &keyboard
+	t.send(edit)
+}
+
+// EditInvalidValue explains that a value sent during a guided edit does not fit its field
+func (t *Telegram) EditInvalidValue(chatID int64, field string) {
+	p := t.Printer(chatID)
+	switch field {
+	case services.EditAmount:
+		t.Text(chatID, p.T("edit.invalid.amount"))
+	case services.EditDate:
+		t.Text(chatID, p.T("edit.invalid.date"))
+	default:
+		t.Text(chatID, p.T("edit.invalid.empty", p.T(editFieldLabels[field])))
+	}
+}
+
+// EditFinished closes the edit menu
+func (t *Telegram) EditFinished(chatID int64, messageID int, expense *models.Expense) {
+	p := t.Printer(chatID)
+	t.EditText(chatID, messageID, p.T("edit.finished", expense.ID, expense.Description, p.Money(expense.Amount), expense.Category))
+}
+
+// ExpenseDeleted confirms a deleted expense and how to bring it back
+func (t *Telegram) ExpenseDeleted(chatID int64, expenseID uint) {
+	t.Message(chatID, "expense.deleted", expenseID, expenseID)
+}
+
+// ExpenseRestored confirms a restored expense
+func (t *Telegram) ExpenseRestored(chatID int64, expense *models.Expense) {
+	p := t.Printer(chatID)
+	t.Text(chatID, p.T("expense.restored", expense.ID, expense.Description, p.Money(expense.Amount), expense.Category))
+}
+
+// Undone describes the change reverted by /undo
+func (t *Telegram) Undone(chatID int64, result *services.UndoResult) {
+	p := t.Printer(chatID)
+	var undoText string
+	switch result.Action {
+	case models.ExpenseCreated:
+		undoText = p.T("undo.created")
+	case models.ExpenseDeleted:
+		undoText = p.T("undo.deleted")
+	case models.ExpenseRestored:
+		undoText = p.T("undo.restored")
+	default:
+		undoText = p.T("undo.updated")
+	}
+	undoText += "\n"
+
+	for i, expense := range result.Expenses {
+		if i == importPreviewRows {
+			undoText += p.T("common.more", len(result.Expenses)-i) + "\n"
+			break
+		}
+		undoText += fmt.Sprintf("ID %d: %s - %s (%s)\n", expense.ID, expense.Description, p.Money(expense.Amount), expense.Category)
+	}
+	t.Text(chatID, undoText)
+}
+
+// ExpenseHistory lists the recorded changes of an expense
+func (t *Telegram) ExpenseHistory(chatID int64, expenseID uint, events []models.ExpenseEvent) {
+	p := t.Printer(chatID)
+	if len(events) == 0 {
+		t.Text(chatID, p.T("history.empty", expenseID))
+		return
+	}
+
+	historyText := p.T("history.title", expenseID) + "\n\n"
+	for _, event := range events {
+		historyText += p.RelativeDateTime(event.CreatedAt) + " - "
+		switch event.Action {
+		case models.ExpenseCreated:
+			historyText += p.T("history.created", describeSnapshot(p, event.After))
+		case models.ExpenseUpdated:
+			historyText += p.T("history.updated", describeSnapshot(p, event.Before), describeSnapshot(p, event.After))
+		case models.ExpenseDeleted:
+			historyText += p.T("history.deleted")
+		case models.ExpenseRestored:
+			historyText += p.T("history.restored")
+		}
+		if event.UndoneAt != nil {
+			historyText += " " + p.T("history.undone", p.RelativeDateTime(*event.UndoneAt))
+		}
+		historyText += "\n"
+	}
+	t.Text(chatID, historyText)
+}
+
+func describeSnapshot(p *i18n.Printer, snapshot *models.ExpenseSnapshot) string {
+	if snapshot == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s, %s, %s", snapshot.Description, p.Money(snapshot.Amount), snapshot.Category)
+}
+
+// Answer shows the result of a question about the user's expenses
+func (t *Telegram) Answer(chatID int64, answer *services.Answer) {
+	p := t.Printer(chatID)
+	scope := describePlan(p, answer)
+	if answer.Count == 0 {
+		t.Text(chatID, p.T("answer.none")+scope)
+		return
+	}
+
+	var answerText string
+	switch answer.Plan.Aggregate {
+	case services.AggregateCount:
+		answerText = p.T("answer.count", answer.Count, p.Money(answer.Total))
+	case services.AggregateAverage:
+		answerText = p.T("answer.average", p.Money(answer.Value), answer.Count, p.Money(answer.Total))
+	case services.AggregateMax:
+		answerText = p.T("answer.max", p.Money(answer.Value))
+	case services.AggregateMin:
+		answerText = p.T("answer.min", p.Money(answer.Value))
+	case services.AggregateLatest:
+		answerText = p.T("answer.latest", p.Date(answer.Rows[0].Date))
+	default:
+		answerText = p.T("answer.total", p.Money(answer.Total), answer.Count)
+	}
+	answerText += scope + "\n"
+
+	if len(answer.Groups) > 0 {
+		answerText += "\n"
+		for _, group := range answer.Groups {
+			label := group.Category
+			if answer.Plan.GroupBy == services.GroupByMonth {
+				label = p.Month(group.Month)
+			}
+			answerText += fmt.Sprintf("- %s: %s (%d)\n", label, p.Money(group.Total), group.Count)
+		}
+	}
+
+	answerText += "\n" + p.T("answer.matches") + "\n"
+	for _, expense := range answer.Rows {
+		answerText += fmt.Sprintf("• ID %d, %s: %s - %s (%s)\n",
+			expense.ID, p.Date(expense.Date), expense.Description, p.Money(expense.Amount), expense.Category)
+	}
+	if len(answer.Rows) < answer.Count {
+		answerText += p.T("common.more", answer.Count-len(answer.Rows))
+	}
+	t.Text(chatID, answerText)
+}
+
+// describePlan lists the period and filters an answer was computed over
+func describePlan(p *i18n.Printer, answer *services.Answer) string {
+	var parts []string
+	switch {
+	case answer.From != nil && answer.To != nil:
+		parts = append(parts, p.T("answer.period", p.Date(*answer.From), p.Date(*answer.To)))
+	case answer.From != nil:
+		parts = append(parts, p.T("answer.since", p.Date(*answer.From)))
+	case answer.To != nil:
+		parts = append(parts, p.T("answer.until", p.Date(*answer.To)))
+	}
+	if answer.Plan.Category != "" {
+		parts = append(parts, p.T("answer.category", answer.Plan.Category))
+	}
+	if len(answer.Plan.Keywords) > 0 {
+		parts = append(parts, p.T("answer.keywords", strings.Join(answer.Plan.Keywords, ", ")))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "\n" + strings.Join(parts, "\n")
+}
+
+// SearchResults shows one page of /cari results; args are the search arguments without the page
+func (t *Telegram) SearchResults(chatID int64, result *services.SearchResult, args string) {
+	p := t.Printer(chatID)
+	if result.Total == 0 {
+		t.Text(chatID, p.T("search.none"))
+		return
+	}
+	if len(result.Expenses) == 0 {
+		t.Text(chatID, p.T("search.no_page", result.Page, result.Pages))
+		return
+	}
+
+	resultText := p.T("search.found", result.Total) + "\n\n"
+	for _, expense := range result.Expenses {
+		resultText += fmt.Sprintf("ID %d, %s: %s", expense.ID, p.Date(expense.Date), expense.Description)
+		if expense.Merchant != "" {
+			resultText += " @ " + expense.Merchant
+		}
+		resultText += fmt.Sprintf(" - %s (%s)", p.Money(expense.Amount), expense.Category)
+		for _, tag := range expense.Tags {
+			resultText += " #" + tag
+		}
+		resultText += "\n"
+	}
+
+	resultText += "\n" + p.T("search.page", result.Page, result.Pages)
+	if result.Page < result.Pages {
+		resultText += "\n" + p.T("search.next", strings.TrimSpace("/cari "+args), result.Page+1)
+	}
+	t.Text(chatID, resultText)
+}
+
+// WeeklyRecap shows the totals per category of the last 7 days
+func (t *Telegram) WeeklyRecap(chatID int64, recap *services.Recap) {
+	p := t.Printer(chatID)
+	if recap.Count == 0 {
+		t.Text(chatID, p.T("recap.weekly.empty"))
+		return
+	}
+
+	recapText := p.T("recap.weekly.title") + "\n"
+	for _, category := range recap.Categories {
+		recapText += fmt.Sprintf("- %s: %s\n", category.Category, p.Money(category.Total))
+	}
+	recapText += p.T("recap.total", p.Money(recap.Total))
+
+	t.Text(chatID, recapText)
+}
+
+// MonthlyRecap lists the expenses of the last 30 days per month
+func (t *Telegram) MonthlyRecap(chatID int64, recap *services.MonthlyRecap) {
+	p := t.Printer(chatID)
+	if len(recap.Months) == 0 {
+		t.Text(chatID, p.T("recap.monthly.empty"))
+		return
+	}
+
+	recapText := p.T("recap.monthly.title") + "\n\n"
+	for _, month := range recap.Months {
+		recapText += fmt.Sprintf("<b>%s (%s)</b>\n", p.Month(month.Month), p.T("recap.total", p.Money(month.Total)))
+		for _, expense := range month.Expenses {
+			recapText += fmt.Sprintf("• %s: %s (%s)\n",
+				p.DayMonth(expense.Date),
+				p.Money(expense.Amount),
+				html.EscapeString(expense.Description))
+		}
+		recapText += "\n"
+	}
+	recapText += "<b>" + p.T("recap.monthly.total", p.Money(recap.Total)) + "</b>"
+
+	t.HTML(chatID, recapText)
+}
+
+// DailyDigest shows today's spending compared to the daily budget
+func (t *Telegram) DailyDigest(chatID int64, digest *services.Digest) {
+	p := t.Printer(chatID)
+	digestText := p.T("digest.title", p.Date(digest.Date)) + "\n"
+
+	if digest.Count == 0 {
+		digestText += p.T("digest.empty") + "\n"
+	} else {
+		for _, category := range digest.Categories {
+			digestText += fmt.Sprintf("- %s: %s\n", category.Category, p.Money(category.Total))
+		}
+		digestText += p.T("recap.total", p.Money(digest.Total)) + "\n"
+	}
+
+	// Compare against the daily budget if one is set
+	if digest.DailyBudget > 0 {
+		digestText += "\n" + p.T("digest.budget", p.Money(digest.DailyBudget)) + "\n"
+		if digest.Total <= digest.DailyBudget {
+			digestText += p.T("digest.remaining", p.Money(digest.DailyBudget-digest.Total))
+		} else {
+			digestText += p.T("digest.over", p.Money(digest.Total-digest.DailyBudget))
+		}
+	}
+
+	t.Text(chatID, digestText)
+}
+
+// InactivityReminder reminds a user to log expenses. The error tells whether it was delivered.
+func (t *Telegram) InactivityReminder(reminder services.Reminder) error {
+	chatID := int64(reminder.UserID)
+	p := t.Printer(chatID)
+	reminderText := p.T("reminder.never")
+	if reminder.LastExpense != nil {
+		reminderText = p.T("reminder.inactive", reminder.DaysInactive)
+	}
+
+	_, err := t.bot.Send(tgbotapi.NewMessage(chatID, reminderText))
+	return err
+}
+
+// DailyBudgetSet confirms a new daily budget
+func (t *Telegram) DailyBudgetSet(chatID int64, amount float64) {
+	p := t.Printer(chatID)
+	responseText := p.T("budget.set", p.Money(amount))
+	if amount <= 0 {
+		responseText = p.T("budget.cleared")
+	}
+	t.Text(chatID, responseText)
+}
+
+// DailyDigestSet confirms the daily digest settings
+func (t *Telegram) DailyDigestSet(chatID int64, pref *models.UserPreference) {
+	p := t.Printer(chatID)
+	responseText := p.T("digest.set", pref.DigestTime)
+	if !pref.DigestEnabled {
+		responseText = p.T("digest.off")
+	}
+	t.Text(chatID, responseText)
+}
+
+// InactivityReminderSet confirms the inactivity reminder settings
+func (t *Telegram) InactivityReminderSet(chatID int64, days int) {
+	p := t.Printer(chatID)
+	responseText := p.T("reminder.set", days)
+	if days <= 0 {
+		responseText = p.T("reminder.off")
+	}
+	t.Text(chatID, responseText)
+}
+
+// Language shows the language of the chat and how to change it
+func (t *Telegram) Language(chatID int64) {
+	p := t.Printer(chatID)
+	var options []string
+	for _, lang := range i18n.Supported {
+		options = append(options, fmt.Sprintf("• /bahasa %s - %s", lang, i18n.NewPrinter(lang, t.location).T("language.name")))
+	}
+	t.Text(chatID, p.T("language.current", p.T("language.name"), strings.Join(options, "\n")))
+}
+
+// LanguageSet confirms a new language, written in that language
+func (t *Telegram) LanguageSet(chatID int64) {
+	p := t.Printer(chatID)
+	t.Text(chatID, p.T("language.set", p.T("language.name")))
+}
+
+// ImportUnreadable explains which statement formats are supported
+func (t *Telegram) ImportUnreadable(chatID int64) {
+	t.Message(chatID, "import.unreadable", strings.Join(importer.Formats(), ", "))
+}
+
+// ImportPreview shows the dry run of an import with buttons to confirm or cancel it
+func (t *Telegram) ImportPreview(chatID int64, preview *services.ImportPreview) {
+	p := t.Printer(chatID)
+	if len(preview.Rows) == 0 {
+		t.Text(chatID, p.T("import.empty", preview.Format))
+		return
+	}
+
+	previewText := p.T("import.title", preview.Format) + "\n\n"
+	for i, row := range preview.Rows {
+		if i == importPreviewRows {
+			previewText += p.T("common.more", len(preview.Rows)-importPreviewRows) + "\n"
+			break
+		}
+
+		marker := "•"
+		if row.Duplicate {
+			marker = "♻️"
+		}
+		previewText += fmt.Sprintf("%s %s: %s (%s) - %s\n",
+			marker,
+			p.Date(row.Date),
+			p.Money(row.Amount),
+			row.Category,
+			row.Description)
+		// Say which stored expense the row repeats so the user can tell why it is skipped
+		if row.DuplicateOf != nil {
+			previewText += "   " + p.T("import.duplicate_of", row.DuplicateOf.ID, row.DuplicateOf.Description) + "\n"
+		}
+	}
+
+	previewText += "\n" + p.T("import.new", preview.NewCount, p.Money(preview.Total)) + "\n"
+	if preview.DuplicateCount > 0 {
+		previewText += p.T("import.duplicates", preview.DuplicateCount) + "\n"
+	}
+	previewText += "\n" + p.T("import.question")
+
+	msg := tgbotapi.NewMessage(chatID, previewText)
+	if preview.NewCount > 0 {
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(p.T("import.confirm"), ImportConfirmCallback),
+				tgbotapi.NewInlineKeyboardButtonData(p.T("import.cancel"), ImportCancelCallback),
+			),
+		)
+	}
+	t.send(msg)
+}
+
+// ImportDone replaces the import preview with the result of the import
+func (t *Telegram) ImportDone(chatID int64, messageID int, result *services.ImportResult) {
+	p := t.Printer(chatID)
+	t.EditText(chatID, messageID, p.T("import.done", result.Count, result.Format, p.Money(result.Total)))
+}
+
+// ExportFiles sends the rendered export files as documents
+func (t *Telegram) ExportFiles(chatID int64, result *services.ExportResult) {
+	p := t.Printer(chatID)
+	if result.Count == 0 {
+		t.Text(chatID, p.T("export.empty"))
+		return
+	}
+
+	for _, format := range result.Failed {
+		t.Text(chatID, p.T("export.file_failed", format))
+	}
+
+	for _, file := range result.Files {
+		doc := tgbotapi.NewDocumentUpload(chatID, tgbotapi.FileBytes{
+			Name:  file.Name,
+			Bytes: file.Data,
+		})
+		doc.Caption = p.T("export.caption", result.Count, p.Money(result.Total))
+		if _, err := t.bot.Send(doc); err != nil {
+			log.Printf("Error sending %s export: %v", file.Format, err)
+		}
+	}
+}
+
+// JobStatuses shows the state of the scheduled jobs
+func (t *Telegram) JobStatuses(chatID int64, statuses []services.JobStatus) {
+	p := t.Printer(chatID)
+	if len(statuses) == 0 {
+		t.Text(chatID, p.T("jobs.empty"))
+		return
+	}
+
+	statusText := p.T("jobs.title") + "\n\n"
+	for _, status := range statuses {
+		lastRun := p.T("common.never")
+		if status.LastRunAt != nil {
+			lastRun = p.RelativeDateTime(*status.LastRunAt)
+		}
+		nextRun := "-"
+		if status.NextRunAt != nil {
+			nextRun = p.RelativeDateTime(*status.NextRunAt)
+		}
+
+		statusText += p.T("jobs.row", status.Name, status.Schedule, lastRun, nextRun, status.RunCount) + "\n"
+		if status.Running {
+			statusText += "   " + p.T("jobs.running") + "\n"
+		}
+		if status.LastError != "" {
+			statusText += "   " + p.T("jobs.error", status.LastError) + "\n"
+		}
+		statusText += "\n"
+	}
+
+	t.Text(chatID, statusText)
+}
+
+// APIToken shows a newly created API token, the only time it is visible
+func (t *Telegram) APIToken(chatID int64, raw string) {
+	t.HTML(chatID, t.Printer(chatID).T("token.created", html.EscapeString(raw)))
+}
+
+// APITokens lists the active API tokens by prefix
+func (t *Telegram) APITokens(chatID int64, tokens []models.APIToken) {
+	p := t.Printer(chatID)
+	if len(tokens) == 0 {
+		t.Text(chatID, p.T("token.none"))
+		return
+	}
+
+	listText := p.T("token.title") + "\n\n"
+	for _, token := range tokens {
+		lastUsed := p.T("common.never")
+		if token.LastUsedAt != nil {
+			lastUsed = p.RelativeDateTime(*token.LastUsedAt)
+		}
+		listText += p.T("token.row", token.Prefix, p.Date(token.CreatedAt), lastUsed) + "\n"
+	}
+	t.Text(chatID, listText)
+}
+
+// DashboardLink sends a one-time dashboard login link
+func (t *Telegram) DashboardLink(chatID int64, link string) {
+	msg := tgbotapi.NewMessage(chatID, t.Printer(chatID).T("dashboard.link", int(services.LoginLinkTTL.Minutes()), link))
+	msg.DisableWebPagePreview = true
+	t.send(msg)
+}
+
+func (t *Telegram) send(msg tgbotapi.Chattable) {
+	if _, err := t.bot.Send(msg); err != nil {
+		log.Printf("Error sending message: %v", err)
+	}
+}
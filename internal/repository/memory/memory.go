@@ -0,0 +1,804 @@
+// Package memory is an in-memory repository.Repository for unit tests.
+// It mirrors the semantics of the GORM store without persistence.
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// Store keeps every record in maps guarded by a single mutex
+type Store struct {
+	mu sync.Mutex
+
+	nextID        uint
+	expenses      map[uint]models.Expense
+	events        []models.ExpenseEvent
+	states        map[int64]models.ChatState
+	confirmations map[string]models.PendingConfirmation
+	preferences   map[uint]models.UserPreference
+	jobs          map[string]models.ScheduledJob
+	updates       map[int]time.Time
+	tokens        map[uint]models.APIToken
+	logins        map[string]models.LoginToken
+	sessions      map[string]models.WebSession
+}
+
+var _ repository.Repository = (*Store)(nil)
+
+func New() *Store {
+	return &Store{
+		expenses:      make(map[uint]models.Expense),
+		states:        make(map[int64]models.ChatState),
+		confirmations: make(map[string]models.PendingConfirmation),
+		preferences:   make(map[uint]models.UserPreference),
+		jobs:          make(map[string]models.ScheduledJob),
+		updates:       make(map[int]time.Time),
+		tokens:        make(map[uint]models.APIToken),
+		logins:        make(map[string]models.LoginToken),
+		sessions:      make(map[string]models.WebSession),
+	}
+}
+
+func (s *Store) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func (s *Store) Close() error {
+	return nil
+}
+
+// Transaction runs fn on the store and puts the previous contents back when fn fails.
+// Unlike a database transaction it does not isolate fn from concurrent writes.
+func (s *Store) Transaction(fn func(tx repository.Repository) error) error {
+	s.mu.Lock()
+	saved := s.clone()
+	s.mu.Unlock()
+
+	if err := fn(s); err != nil {
+		s.mu.Lock()
+		s.nextID, s.expenses, s.events, s.states, s.preferences = saved.nextID, saved.expenses, saved.events, saved.states, saved.preferences
+		s.jobs, s.updates, s.tokens, s.logins, s.sessions = saved.jobs, saved.updates, saved.tokens, saved.logins, saved.sessions
+		s.confirmations = saved.confirmations
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// clone copies the contents of the store; records are values, so copying the maps is enough
+func (s *Store) clone() *Store {
+	return &Store{
+		nextID:        s.nextID,
+		expenses:      maps.Clone(s.expenses),
+		events:        slices.Clone(s.events),
+		states:        maps.Clone(s.states),
+		confirmations: maps.Clone(s.confirmations),
+		preferences:   maps.Clone(s.preferences),
+		jobs:          maps.Clone(s.jobs),
+		updates:       maps.Clone(s.updates),
+		tokens:        maps.Clone(s.tokens),
+		logins:        maps.Clone(s.logins),
+		sessions:      maps.Clone(s.sessions),
+	}
+}
+
+// id hands out IDs shared by all tables, which is enough to keep them unique per table
+func (s *Store) id() uint {
+	s.nextID++
+	return s.nextID
+}
+
+func (s *Store) CreateExpense(expense *models.Expense) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.createExpense(expense)
+	return nil
+}
+
+func (s *Store) CreateExpenses(expenses []models.Expense) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range expenses {
+		s.createExpense(&expenses[i])
+	}
+	return nil
+}
+
+func (s *Store) createExpense(expense *models.Expense) {
+	now := time.Now()
+	if expense.ID == 0 {
+		expense.ID = s.id()
+	}
+	expense.CreatedAt = now
+	expense.UpdatedAt = now
+	s.expenses[expense.ID] = *expense
+}
+
+func (s *Store) GetExpenseByID(userID uint, expenseID uint) (*models.Expense, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expense, ok := s.expenses[expenseID]
+	if !ok || expense.UserID != userID || expense.DeletedAt.Valid {
+		return nil, repository.ErrNotFound
+	}
+	return &expense, nil
+}
+
+func (s *Store) UpdateExpense(expense *models.Expense) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expense.UpdatedAt = time.Now()
+	s.expenses[expense.ID] = *expense
+	return nil
+}
+
+func (s *Store) DeleteExpense(userID uint, expenseID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expense, ok := s.expenses[expenseID]; ok && expense.UserID == userID && !expense.DeletedAt.Valid {
+		expense.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		s.expenses[expenseID] = expense
+	}
+	return nil
+}
+
+func (s *Store) GetDeletedExpenseByID(userID uint, expenseID uint) (*models.Expense, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expense, ok := s.expenses[expenseID]
+	if !ok || expense.UserID != userID || !expense.DeletedAt.Valid {
+		return nil, repository.ErrNotFound
+	}
+	return &expense, nil
+}
+
+func (s *Store) RestoreExpense(userID uint, expenseID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expense, ok := s.expenses[expenseID]; ok && expense.UserID == userID {
+		expense.DeletedAt = gorm.DeletedAt{}
+		s.expenses[expenseID] = expense
+	}
+	return nil
+}
+
+func (s *Store) GetExpensesFiltered(userID uint, filter repository.ExpenseFilter) ([]models.Expense, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expenses := s.filteredExpenses(userID, filter)
+	sortExpenses(expenses, "date", true)
+	return expenses, nil
+}
+
+func (s *Store) ListExpensesPage(userID uint, filter repository.ExpenseFilter, sortColumn string, descending bool, limit int, offset int) ([]models.Expense, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expenses := s.filteredExpenses(userID, filter)
+	sortExpenses(expenses, sortColumn, descending)
+
+	total := int64(len(expenses))
+	if offset > len(expenses) {
+		offset = len(expenses)
+	}
+	expenses = expenses[offset:]
+	if limit >= 0 && limit < len(expenses) {
+		expenses = expenses[:limit]
+	}
+	return expenses, total, nil
+}
+
+func (s *Store) ListExpensesByCursor(userID uint, cursor *repository.ExpenseCursor, newer bool, limit int) ([]models.Expense, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expenses := s.filteredExpenses(userID, repository.ExpenseFilter{})
+	sortExpenses(expenses, "date", true)
+
+	var page []models.Expense
+	for _, expense := range expenses {
+		if cursor == nil {
+			page = append(page, expense)
+			continue
+		}
+		isNewer := expense.Date.After(cursor.Date) || (expense.Date.Equal(cursor.Date) && expense.ID > cursor.ID)
+		isOlder := expense.Date.Before(cursor.Date) || (expense.Date.Equal(cursor.Date) && expense.ID < cursor.ID)
+		if (newer && isNewer) || (!newer && isOlder) {
+			page = append(page, expense)
+		}
+	}
+
+	if len(page) > limit {
+		if newer && cursor != nil {
+			// Keep the rows right after the cursor
+			page = page[len(page)-limit:]
+		} else {
+			page = page[:limit]
+		}
+	}
+	return page, nil
+}
+
+func (s *Store) GetCategoryTotals(userID uint, filter repository.ExpenseFilter) ([]repository.CategoryTotal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byCategory := make(map[string]*repository.CategoryTotal)
+	for _, expense := range s.filteredExpenses(userID, filter) {
+		total, ok := byCategory[expense.Category]
+		if !ok {
+			total = &repository.CategoryTotal{Category: expense.Category}
+			byCategory[expense.Category] = total
+		}
+		total.Count++
+		total.Total += expense.Amount
+	}
+
+	result := make([]repository.CategoryTotal, 0, len(byCategory))
+	for _, total := range byCategory {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Category < result[j].Category
+	})
+	return result, nil
+}
+
+func (s *Store) GetRecentExpensesByAmount(userID uint, amount float64, since time.Time) ([]models.Expense, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expenses []models.Expense
+	for _, expense := range s.expenses {
+		if expense.UserID == userID && !expense.DeletedAt.Valid && expense.Amount == amount && !expense.CreatedAt.Before(since) {
+			expenses = append(expenses, expense)
+		}
+	}
+	sortExpenses(expenses, "created_at", true)
+	return expenses, nil
+}
+
+func (s *Store) GetExpensesByAmount(userID uint, amount float64, from time.Time, to time.Time) ([]models.Expense, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expenses []models.Expense
+	for _, expense := range s.expenses {
+		if expense.UserID == userID && !expense.DeletedAt.Valid && expense.Amount == amount && !expense.Date.Before(from) && expense.Date.Before(to) {
+			expenses = append(expenses, expense)
+		}
+	}
+	sortExpenses(expenses, "date", false)
+	return expenses, nil
+}
+
+func (s *Store) GetLastExpense(userID uint) (*models.Expense, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var last *models.Expense
+	for _, expense := range s.expenses {
+		if expense.UserID != userID || expense.DeletedAt.Valid {
+			continue
+		}
+		if last == nil || expense.CreatedAt.After(last.CreatedAt) {
+			e := expense
+			last = &e
+		}
+	}
+	return last, nil
+}
+
+func (s *Store) CreateExpenseEvents(events []models.ExpenseEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range events {
+		events[i].ID = s.id()
+		events[i].CreatedAt = time.Now()
+		s.events = append(s.events, events[i])
+	}
+	return nil
+}
+
+func (s *Store) GetExpenseEvents(userID uint, expenseID uint) ([]models.ExpenseEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []models.ExpenseEvent
+	for _, event := range s.events {
+		if event.UserID == userID && event.ExpenseID == expenseID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (s *Store) GetLastExpenseEvents(userID uint) ([]models.ExpenseEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Events are appended in ID order, so the last pending one belongs to the latest batch
+	batch := ""
+	for i := len(s.events) - 1; i >= 0; i-- {
+		if s.events[i].UserID == userID && s.events[i].UndoneAt == nil {
+			batch = s.events[i].Batch
+			break
+		}
+	}
+	if batch == "" {
+		return nil, nil
+	}
+
+	var events []models.ExpenseEvent
+	for _, event := range s.events {
+		if event.UserID == userID && event.Batch == batch && event.UndoneAt == nil {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (s *Store) MarkExpenseEventsUndone(userID uint, batch string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.events {
+		if s.events[i].UserID == userID && s.events[i].Batch == batch && s.events[i].UndoneAt == nil {
+			s.events[i].UndoneAt = &at
+		}
+	}
+	return nil
+}
+
+// filteredExpenses applies the filter the same way the SQL query does; the caller holds the lock
+func (s *Store) filteredExpenses(userID uint, filter repository.ExpenseFilter) []models.Expense {
+	var expenses []models.Expense
+	for _, expense := range s.expenses {
+		if expense.UserID != userID || expense.DeletedAt.Valid {
+			continue
+		}
+		if filter.From != nil && expense.Date.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !expense.Date.Before(*filter.To) {
+			continue
+		}
+		if filter.Category != "" && !strings.EqualFold(expense.Category, filter.Category) {
+			continue
+		}
+		if filter.Tag != "" && !expense.Tags.Contains(filter.Tag) {
+			continue
+		}
+		if filter.Query != "" && !strings.Contains(strings.ToLower(expense.Description), strings.ToLower(filter.Query)) &&
+			!strings.Contains(strings.ToLower(expense.Merchant), strings.ToLower(filter.Query)) {
+			continue
+		}
+		if filter.MinAmount != nil && expense.Amount < *filter.MinAmount {
+			continue
+		}
+		if filter.MaxAmount != nil && expense.Amount > *filter.MaxAmount {
+			continue
+		}
+		expenses = append(expenses, expense)
+	}
+	return expenses
+}
+
+// sortExpenses orders by one of repository.ExpenseSortColumns, then by ID descending
+func sortExpenses(expenses []models.Expense, column string, descending bool) {
+	less := func(a models.Expense, b models.Expense) (bool, bool) {
+		switch column {
+		case "date":
+			return a.Date.Before(b.Date), a.Date.Equal(b.Date)
+		case "amount":
+			return a.Amount < b.Amount, a.Amount == b.Amount
+		case "category":
+			return a.Category < b.Category, a.Category == b.Category
+		case "created_at":
+			return a.CreatedAt.Before(b.CreatedAt), a.CreatedAt.Equal(b.CreatedAt)
+		default:
+			return a.ID < b.ID, a.ID == b.ID
+		}
+	}
+
+	sort.SliceStable(expenses, func(i, j int) bool {
+		isLess, equal := less(expenses[i], expenses[j])
+		if equal {
+			return expenses[i].ID > expenses[j].ID
+		}
+		if descending {
+			return !isLess
+		}
+		return isLess
+	})
+}
+
+func (s *Store) GetChatState(chatID int64) (*models.ChatState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[chatID]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func (s *Store) SaveChatState(state *models.ChatState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state.UpdatedAt = time.Now()
+	s.states[state.ChatID] = *state
+	return nil
+}
+
+func (s *Store) DeleteChatState(chatID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, chatID)
+	return nil
+}
+
+func (s *Store) SavePendingConfirmation(confirmation *models.PendingConfirmation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if confirmation.CreatedAt.IsZero() {
+		confirmation.CreatedAt = time.Now()
+	}
+	s.confirmations[confirmation.Key] = *confirmation
+	return nil
+}
+
+func (s *Store) TakePendingConfirmation(userID uint, key string, now time.Time) (*models.PendingConfirmation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	confirmation, ok := s.confirmations[key]
+	if !ok || confirmation.UserID != userID {
+		return nil, nil
+	}
+	delete(s.confirmations, key)
+	if !confirmation.ExpiresAt.After(now) {
+		return nil, nil
+	}
+	return &confirmation, nil
+}
+
+func (s *Store) DeleteExpiredPendingConfirmations(now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, confirmation := range s.confirmations {
+		if confirmation.ExpiresAt.Before(now) {
+			delete(s.confirmations, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) GetUserPreference(userID uint) (*models.UserPreference, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pref, ok := s.preferences[userID]
+	if !ok {
+		pref = models.UserPreference{ID: s.id(), UserID: userID, DigestTime: "21:00", CreatedAt: time.Now()}
+		s.preferences[userID] = pref
+	}
+	return &pref, nil
+}
+
+func (s *Store) SaveUserPreference(pref *models.UserPreference) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pref.ID == 0 {
+		pref.ID = s.id()
+	}
+	pref.UpdatedAt = time.Now()
+	s.preferences[pref.UserID] = *pref
+	return nil
+}
+
+func (s *Store) GetDigestPreferences() ([]models.UserPreference, error) {
+	return s.preferencesWhere(func(pref models.UserPreference) bool { return pref.DigestEnabled })
+}
+
+func (s *Store) GetReminderPreferences() ([]models.UserPreference, error) {
+	return s.preferencesWhere(func(pref models.UserPreference) bool { return pref.ReminderDays > 0 })
+}
+
+func (s *Store) preferencesWhere(match func(models.UserPreference) bool) ([]models.UserPreference, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var prefs []models.UserPreference
+	for _, pref := range s.preferences {
+		if match(pref) {
+			prefs = append(prefs, pref)
+		}
+	}
+	sort.Slice(prefs, func(i, j int) bool { return prefs[i].UserID < prefs[j].UserID })
+	return prefs, nil
+}
+
+func (s *Store) RegisterScheduledJob(name string, schedule string) (*models.ScheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		job = models.ScheduledJob{Name: name, CreatedAt: time.Now()}
+	}
+	job.Schedule = schedule
+	s.jobs[name] = job
+	return &job, nil
+}
+
+func (s *Store) GetScheduledJob(name string) (*models.ScheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &job, nil
+}
+
+func (s *Store) GetScheduledJobs() ([]models.ScheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]models.ScheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs, nil
+}
+
+func (s *Store) DeleteScheduledJob(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, name)
+	return nil
+}
+
+func (s *Store) AcquireJobLock(name string, owner string, until time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return false, nil
+	}
+	if job.LockedUntil != nil && !job.LockedUntil.Before(time.Now()) && job.LockedBy != owner {
+		return false, nil
+	}
+
+	job.LockedBy = owner
+	job.LockedUntil = &until
+	s.jobs[name] = job
+	return true, nil
+}
+
+func (s *Store) ReleaseJobLock(name string, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[name]; ok && job.LockedBy == owner {
+		job.LockedBy = ""
+		job.LockedUntil = nil
+		s.jobs[name] = job
+	}
+	return nil
+}
+
+func (s *Store) FinishJobRun(name string, owner string, ranAt time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[name]; ok && job.LockedBy == owner {
+		job.LastRunAt = &ranAt
+		job.LastError = lastError
+		job.RunCount++
+		job.LockedBy = ""
+		job.LockedUntil = nil
+		s.jobs[name] = job
+	}
+	return nil
+}
+
+func (s *Store) MarkUpdateProcessed(updateID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.updates[updateID]; ok {
+		return false, nil
+	}
+	s.updates[updateID] = time.Now()
+	return true, nil
+}
+
+func (s *Store) UnmarkUpdateProcessed(updateID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.updates, updateID)
+	return nil
+}
+
+func (s *Store) DeleteProcessedUpdatesBefore(before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for id, createdAt := range s.updates {
+		if createdAt.Before(before) {
+			delete(s.updates, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) CreateAPIToken(token *models.APIToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token.ID = s.id()
+	token.CreatedAt = time.Now()
+	s.tokens[token.ID] = *token
+	return nil
+}
+
+func (s *Store) GetAPITokenByHash(tokenHash string) (*models.APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (s *Store) GetAPITokensByUserID(userID uint) ([]models.APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tokens []models.APIToken
+	for _, token := range s.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].ID > tokens[j].ID })
+	return tokens, nil
+}
+
+func (s *Store) TouchAPIToken(tokenID uint, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token, ok := s.tokens[tokenID]; ok {
+		token.LastUsedAt = &usedAt
+		s.tokens[tokenID] = token
+	}
+	return nil
+}
+
+func (s *Store) DeleteAPITokens(userID uint) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for id, token := range s.tokens {
+		if token.UserID == userID {
+			delete(s.tokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) CreateLoginToken(token *models.LoginToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token.ID = s.id()
+	token.CreatedAt = time.Now()
+	s.logins[token.TokenHash] = *token
+	return nil
+}
+
+func (s *Store) UseLoginToken(tokenHash string, now time.Time) (*models.LoginToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.logins[tokenHash]
+	if !ok || token.UsedAt != nil || !token.ExpiresAt.After(now) {
+		return nil, nil
+	}
+
+	token.UsedAt = &now
+	s.logins[tokenHash] = token
+	return &token, nil
+}
+
+func (s *Store) CreateWebSession(session *models.WebSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session.ID = s.id()
+	session.CreatedAt = time.Now()
+	s.sessions[session.TokenHash] = *session
+	return nil
+}
+
+func (s *Store) GetWebSession(tokenHash string, now time.Time) (*models.WebSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[tokenHash]
+	if !ok || !session.ExpiresAt.After(now) {
+		return nil, repository.ErrNotFound
+	}
+	return &session, nil
+}
+
+func (s *Store) DeleteWebSession(tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, tokenHash)
+	return nil
+}
+
+func (s *Store) DeleteExpiredLogins(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for hash, token := range s.logins {
+		if token.ExpiresAt.Before(now) {
+			delete(s.logins, hash)
+		}
+	}
+	for hash, session := range s.sessions {
+		if session.ExpiresAt.Before(now) {
+			delete(s.sessions, hash)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+// ErrNotFound is returned when a single requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// ExpenseFilter narrows down a user's expenses; zero values are ignored
+type ExpenseFilter struct {
+	From     *time.Time
+	To       *time.Time
+	Category string
+	// Tag matches one of the expense's tags
+	Tag string
+	// Query is searched in the description and the merchant
+	Query     string
+	MinAmount *float64
+	MaxAmount *float64
+}
+
+// ExpenseSortColumns maps the sort keys accepted by the API to columns
+var ExpenseSortColumns = map[string]string{
+	"date":       "date",
+	"amount":     "amount",
+	"category":   "category",
+	"created_at": "created_at",
+	"id":         "id",
+}
+
+// ExpenseCursor is a position in a user's expenses ordered by date and then ID, newest first
+type ExpenseCursor struct {
+	Date time.Time
+	ID   uint
+}
+
+// CategoryTotal is the aggregate of one category
+type CategoryTotal struct {
+	Category string  `json:"category"`
+	Count    int64   `json:"count"`
+	Total    float64 `json:"total"`
+}
+
+// Expenses stores the expenses of all users
+type Expenses interface {
+	CreateExpense(expense *models.Expense) error
+	// CreateExpenses saves several expenses in a single transaction
+	CreateExpenses(expenses []models.Expense) error
+	// GetExpenseByID returns ErrNotFound when the user has no such expense
+	GetExpenseByID(userID uint, expenseID uint) (*models.Expense, error)
+	UpdateExpense(expense *models.Expense) error
+	DeleteExpense(userID uint, expenseID uint) error
+	// GetDeletedExpenseByID returns a soft-deleted expense, or ErrNotFound when the user has no such deleted expense
+	GetDeletedExpenseByID(userID uint, expenseID uint) (*models.Expense, error)
+	// RestoreExpense brings back a soft-deleted expense
+	RestoreExpense(userID uint, expenseID uint) error
+
+	// GetExpensesFiltered returns a user's expenses matching the filter, newest first
+	GetExpensesFiltered(userID uint, filter ExpenseFilter) ([]models.Expense, error)
+	// ListExpensesPage returns one page of a user's expenses matching the filter along with the total count.
+	// sortColumn must be a value of ExpenseSortColumns.
+	ListExpensesPage(userID uint, filter ExpenseFilter, sortColumn string, descending bool, limit int, offset int) ([]models.Expense, int64, error)
+	// ListExpensesByCursor returns up to limit of a user's expenses older than the cursor, or newer when newer
+	// is set, ordered newest first. A nil cursor starts at the newest expense.
+	ListExpensesByCursor(userID uint, cursor *ExpenseCursor, newer bool, limit int) ([]models.Expense, error)
+	// GetCategoryTotals returns the number and sum of a user's expenses per category, largest total first
+	GetCategoryTotals(userID uint, filter ExpenseFilter) ([]CategoryTotal, error)
+
+	// GetRecentExpensesByAmount returns the user's expenses with the given amount created since the given time
+	GetRecentExpensesByAmount(userID uint, amount float64, since time.Time) ([]models.Expense, error)
+	// GetExpensesByAmount returns the user's expenses with the given amount dated in the period, oldest first
+	GetExpensesByAmount(userID uint, amount float64, from time.Time, to time.Time) ([]models.Expense, error)
+	// GetLastExpense returns the most recently logged expense of a user, or nil if there is none
+	GetLastExpense(userID uint) (*models.Expense, error)
+}
+
+// Audit stores the history of changes to expenses
+type Audit interface {
+	CreateExpenseEvents(events []models.ExpenseEvent) error
+	// GetExpenseEvents returns the events of one expense, oldest first
+	GetExpenseEvents(userID uint, expenseID uint) ([]models.ExpenseEvent, error)
+	// GetLastExpenseEvents returns the events of the user's most recent batch that has not been undone,
+	// or none when there is nothing left to undo
+	GetLastExpenseEvents(userID uint) ([]models.ExpenseEvent, error)
+	// MarkExpenseEventsUndone records that the events of a batch were undone
+	MarkExpenseEventsUndone(userID uint, batch string, at time.Time) error
+}
+
+// States stores the conversation state of each chat
+type States interface {
+	// GetChatState returns the state of a chat, or nil when it is not in a conversation
+	GetChatState(chatID int64) (*models.ChatState, error)
+	// SaveChatState creates or replaces the state of a chat
+	SaveChatState(state *models.ChatState) error
+	DeleteChatState(chatID int64) error
+}
+
+// Confirmations stores actions waiting for the user to confirm them
+type Confirmations interface {
+	// SavePendingConfirmation creates or replaces a pending confirmation
+	SavePendingConfirmation(confirmation *models.PendingConfirmation) error
+	// TakePendingConfirmation removes an unexpired pending confirmation of the user and returns it,
+	// or nil when there is no such confirmation. Each confirmation is only taken once.
+	TakePendingConfirmation(userID uint, key string, now time.Time) (*models.PendingConfirmation, error)
+	// DeleteExpiredPendingConfirmations removes confirmations nobody answered in time
+	DeleteExpiredPendingConfirmations(now time.Time) (int64, error)
+}
+
+// Preferences stores per-user settings
+type Preferences interface {
+	// GetUserPreference returns the preferences for a user, creating the default row if none exists yet
+	GetUserPreference(userID uint) (*models.UserPreference, error)
+	SaveUserPreference(pref *models.UserPreference) error
+	// GetDigestPreferences returns every preference row that has the daily digest enabled
+	GetDigestPreferences() ([]models.UserPreference, error)
+	// GetReminderPreferences returns every preference row that has the inactivity reminder enabled
+	GetReminderPreferences() ([]models.UserPreference, error)
+}
+
+// Jobs persists scheduled jobs and their locks, shared by all instances
+type Jobs interface {
+	// RegisterScheduledJob creates the job row if needed and keeps its schedule up to date,
+	// without touching the run history
+	RegisterScheduledJob(name string, schedule string) (*models.ScheduledJob, error)
+	GetScheduledJob(name string) (*models.ScheduledJob, error)
+	GetScheduledJobs() ([]models.ScheduledJob, error)
+	DeleteScheduledJob(name string) error
+	// AcquireJobLock takes the lock of a job for the given owner until the given time.
+	// It returns false when another instance currently holds an unexpired lock.
+	AcquireJobLock(name string, owner string, until time.Time) (bool, error)
+	// ReleaseJobLock releases the lock of a job without recording a run
+	ReleaseJobLock(name string, owner string) error
+	// FinishJobRun records a completed run and releases the lock held by owner
+	FinishJobRun(name string, owner string, ranAt time.Time, lastError string) error
+}
+
+// Updates remembers handled Telegram update_ids so retries are ignored
+type Updates interface {
+	// MarkUpdateProcessed records an update_id and reports whether it was seen for the first time
+	MarkUpdateProcessed(updateID int) (bool, error)
+	// UnmarkUpdateProcessed forgets an update_id so a redelivery of it is processed
+	UnmarkUpdateProcessed(updateID int) error
+	// DeleteProcessedUpdatesBefore forgets update_ids older than the given time
+	DeleteProcessedUpdatesBefore(before time.Time) (int64, error)
+}
+
+// Tokens stores REST API tokens
+type Tokens interface {
+	CreateAPIToken(token *models.APIToken) error
+	GetAPITokenByHash(tokenHash string) (*models.APIToken, error)
+	GetAPITokensByUserID(userID uint) ([]models.APIToken, error)
+	// TouchAPIToken records when a token was last used
+	TouchAPIToken(tokenID uint, usedAt time.Time) error
+	// DeleteAPITokens revokes every token of a user
+	DeleteAPITokens(userID uint) (int64, error)
+}
+
+// Sessions stores dashboard login links and browser sessions
+type Sessions interface {
+	CreateLoginToken(token *models.LoginToken) error
+	// UseLoginToken marks an unexpired, unused login token as used and returns it,
+	// or nil when there is no such token. A link can only be redeemed once.
+	UseLoginToken(tokenHash string, now time.Time) (*models.LoginToken, error)
+	CreateWebSession(session *models.WebSession) error
+	// GetWebSession returns the unexpired session with the given token hash
+	GetWebSession(tokenHash string, now time.Time) (*models.WebSession, error)
+	DeleteWebSession(tokenHash string) error
+	// DeleteExpiredLogins removes expired login tokens and sessions
+	DeleteExpiredLogins(now time.Time) error
+}
+
+// Repository is the complete storage used by the services
+type Repository interface {
+	Expenses
+	Audit
+	States
+	Confirmations
+	Preferences
+	Jobs
+	Updates
+	Tokens
+	Sessions
+
+	// Transaction runs fn with a repository whose writes are saved together when fn returns nil
+	// and discarded when it returns an error
+	Transaction(fn func(tx Repository) error) error
+
+	// Ping checks that the storage is reachable
+	Ping(ctx context.Context) error
+	Close() error
+}
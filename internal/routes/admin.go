@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requireAdminToken protects operational endpoints with the ADMIN_TOKEN setting,
+// sent as "Authorization: Bearer TOKEN" or "X-Admin-Token: TOKEN". Without ADMIN_TOKEN they are disabled.
+func (h *Handlers) requireAdminToken(c *fiber.Ctx) error {
+	if h.adminToken == "" {
+		return c.Status(403).JSON(fiber.Map{"error": "Admin endpoints are disabled, set ADMIN_TOKEN to enable them"})
+	}
+
+	provided := c.Get("X-Admin-Token")
+	if provided == "" {
+		provided = strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	}
+
+	if !secretsEqual(provided, h.adminToken) {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	return c.Next()
+}
+
+// secretsEqual compares secrets in constant time
+func secretsEqual(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
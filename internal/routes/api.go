@@ -0,0 +1,375 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"SmartExpenseAI/internal/export"
+	"SmartExpenseAI/internal/i18n"
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// expenseInput is the JSON body for creating and updating expenses; omitted fields are left unchanged
+type expenseInput struct {
+	Description *string   `json:"description"`
+	Category    *string   `json:"category"`
+	Merchant    *string   `json:"merchant"`
+	Account     *string   `json:"account"`
+	Tags        *[]string `json:"tags"`
+	Amount      *float64  `json:"amount"`
+	Date        *string   `json:"date"`
+}
+
+func (h *Handlers) APIRoutes(app *fiber.App) {
+	api := app.Group("/api/v1", h.requireAPIToken)
+
+	api.Get("/expenses", h.listExpensesHandler)
+	api.Post("/expenses", h.createExpenseHandler)
+	api.Get("/expenses/export", h.exportExpensesHandler)
+	api.Get("/expenses/:id", h.getExpenseHandler)
+	api.Put("/expenses/:id", h.updateExpenseHandler)
+	api.Patch("/expenses/:id", h.updateExpenseHandler)
+	api.Delete("/expenses/:id", h.deleteExpenseHandler)
+
+	api.Get("/categories", h.listCategoriesHandler)
+
+	api.Get("/budgets", h.getBudgetsHandler)
+	api.Put("/budgets", h.updateBudgetsHandler)
+
+	api.Get("/recaps", h.getRecapHandler)
+}
+
+// requireAPIToken authenticates the request with a per-user bearer token generated via /token
+func (h *Handlers) requireAPIToken(c *fiber.Ctx) error {
+	header := c.Get(fiber.HeaderAuthorization)
+	if !strings.HasPrefix(header, "Bearer ") {
+		return c.Status(401).JSON(fiber.Map{"error": "Missing bearer token"})
+	}
+
+	token, err := h.service.AuthenticateAPIToken(strings.TrimPrefix(header, "Bearer "))
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": "Invalid token"})
+	}
+
+	c.Locals("userID", token.UserID)
+	return c.Next()
+}
+
+func apiUserID(c *fiber.Ctx) uint {
+	return c.Locals("userID").(uint)
+}
+
+func (h *Handlers) listExpensesHandler(c *fiber.Ctx) error {
+	filter, err := h.parseExpenseFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page := c.QueryInt("page", 1)
+	perPage := c.QueryInt("per_page", defaultPageSize)
+	if page < 1 || perPage < 1 || perPage > maxPageSize {
+		return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("page must be >= 1 and per_page between 1 and %d", maxPageSize)})
+	}
+
+	sortKey := c.Query("sort", "-date")
+	descending := strings.HasPrefix(sortKey, "-")
+	sortColumn, ok := repository.ExpenseSortColumns[strings.TrimPrefix(sortKey, "-")]
+	if !ok {
+		return c.Status(400).JSON(fiber.Map{"error": "sort must be one of date, amount, category, created_at, id (prefix with - for descending)"})
+	}
+
+	expenses, total, err := h.service.ListExpensesPage(apiUserID(c), filter, sortColumn, descending, perPage, (page-1)*perPage)
+	if err != nil {
+		log.Printf("Error listing expenses: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load expenses"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":     expenses,
+		"page":     page,
+		"per_page": perPage,
+		"total":    total,
+	})
+}
+
+func (h *Handlers) getExpenseHandler(c *fiber.Ctx) error {
+	expense, ferr := h.loadExpense(c)
+	if ferr != nil {
+		return c.Status(ferr.Code).JSON(fiber.Map{"error": ferr.Message})
+	}
+	return c.JSON(expense)
+}
+
+func (h *Handlers) createExpenseHandler(c *fiber.Ctx) error {
+	var input expenseInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+
+	if input.Description == nil || input.Amount == nil {
+		return c.Status(400).JSON(fiber.Map{"error": "description and amount are required"})
+	}
+
+	expense := models.Expense{
+		UserID: apiUserID(c),
+		Date:   time.Now(),
+	}
+	if err := input.apply(&expense, h.location); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.service.CreateExpense(&expense); err != nil {
+		log.Printf("Error creating expense: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create expense"})
+	}
+
+	return c.Status(201).JSON(expense)
+}
+
+func (h *Handlers) updateExpenseHandler(c *fiber.Ctx) error {
+	expense, ferr := h.loadExpense(c)
+	if ferr != nil {
+		return c.Status(ferr.Code).JSON(fiber.Map{"error": ferr.Message})
+	}
+
+	var input expenseInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+	if err := input.apply(expense, h.location); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.service.UpdateExpense(expense); err != nil {
+		log.Printf("Error updating expense: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update expense"})
+	}
+
+	return c.JSON(expense)
+}
+
+func (h *Handlers) deleteExpenseHandler(c *fiber.Ctx) error {
+	expense, ferr := h.loadExpense(c)
+	if ferr != nil {
+		return c.Status(ferr.Code).JSON(fiber.Map{"error": ferr.Message})
+	}
+
+	if err := h.service.DeleteExpense(expense.UserID, expense.ID); err != nil {
+		log.Printf("Error deleting expense: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete expense"})
+	}
+
+	return c.SendStatus(204)
+}
+
+func (h *Handlers) exportExpensesHandler(c *fiber.Ctx) error {
+	format, err := export.ParseFormat(strings.ToLower(c.Query("format", export.FormatCSV)))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	filter, err := h.parseExpenseFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userID := apiUserID(c)
+	expenses, err := h.service.ListExpenses(userID, filter)
+	if err != nil {
+		log.Printf("Error fetching expenses for export: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load expenses"})
+	}
+
+	printer := i18n.NewPrinter(h.Language(int64(userID)), h.location)
+	data, err := export.Render(format, expenses, printer)
+	if err != nil {
+		log.Printf("Error rendering %s export: %v", format, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to render export"})
+	}
+
+	c.Set(fiber.HeaderContentType, export.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", export.FileName(format, time.Now(), printer)))
+	return c.Send(data)
+}
+
+func (h *Handlers) listCategoriesHandler(c *fiber.Ctx) error {
+	filter, err := h.parseExpenseFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	totals, err := h.service.CategoryTotals(apiUserID(c), filter)
+	if err != nil {
+		log.Printf("Error fetching categories: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load categories"})
+	}
+
+	return c.JSON(fiber.Map{"data": totals})
+}
+
+func (h *Handlers) getBudgetsHandler(c *fiber.Ctx) error {
+	pref, err := h.service.Preferences(apiUserID(c))
+	if err != nil {
+		log.Printf("Error fetching preferences: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load budgets"})
+	}
+
+	return c.JSON(fiber.Map{"daily": pref.DailyBudget})
+}
+
+func (h *Handlers) updateBudgetsHandler(c *fiber.Ctx) error {
+	var input struct {
+		Daily *float64 `json:"daily"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON body"})
+	}
+	if input.Daily == nil || *input.Daily < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "daily must be a non-negative number"})
+	}
+
+	pref, err := h.service.SetDailyBudget(apiUserID(c), *input.Daily)
+	if err != nil {
+		log.Printf("Error saving preferences: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save budgets"})
+	}
+
+	return c.JSON(fiber.Map{"daily": pref.DailyBudget})
+}
+
+func (h *Handlers) getRecapHandler(c *fiber.Ctx) error {
+	now := time.Now().In(h.location)
+	to := now
+	var from time.Time
+
+	switch c.Query("period", "weekly") {
+	case "daily":
+		from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, h.location)
+	case "weekly":
+		from = now.AddDate(0, 0, -7)
+	case "monthly":
+		from = now.AddDate(0, 0, -30)
+	case "custom":
+		start, err := parseExportDate(c.Query("from"), false, h.location)
+		if err != nil || start == nil {
+			return c.Status(400).JSON(fiber.Map{"error": "from is required in YYYY-MM-DD format for a custom period"})
+		}
+		end, err := parseExportDate(c.Query("to"), true, h.location)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "to must be in YYYY-MM-DD format"})
+		}
+		from = *start
+		if end != nil {
+			to = *end
+		}
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "period must be one of daily, weekly, monthly, custom"})
+	}
+
+	recap, err := h.service.BuildRecap(apiUserID(c), from, to)
+	if err != nil {
+		log.Printf("Error building recap: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to build recap"})
+	}
+
+	return c.JSON(recap)
+}
+
+// loadExpense fetches the expense named by the :id parameter
+func (h *Handlers) loadExpense(c *fiber.Ctx) (*models.Expense, *fiber.Error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return nil, fiber.NewError(400, "id must be a number")
+	}
+
+	expense, err := h.service.GetExpense(apiUserID(c), uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fiber.NewError(404, "Expense not found")
+	}
+	if err != nil {
+		log.Printf("Error fetching expense: %v", err)
+		return nil, fiber.NewError(500, "Failed to load expense")
+	}
+	return expense, nil
+}
+
+// parseExpenseFilter reads the from, to, category, tag, q, min_amount and max_amount query parameters
+func (h *Handlers) parseExpenseFilter(c *fiber.Ctx) (repository.ExpenseFilter, error) {
+	filter := repository.ExpenseFilter{
+		Category: c.Query("category"),
+		Tag:      c.Query("tag"),
+		Query:    c.Query("q"),
+	}
+
+	var err error
+	if filter.From, err = parseExportDate(c.Query("from"), false, h.location); err != nil {
+		return filter, fmt.Errorf("from must be in YYYY-MM-DD format")
+	}
+	if filter.To, err = parseExportDate(c.Query("to"), true, h.location); err != nil {
+		return filter, fmt.Errorf("to must be in YYYY-MM-DD format")
+	}
+
+	for name, target := range map[string]**float64{"min_amount": &filter.MinAmount, "max_amount": &filter.MaxAmount} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		amount, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be a number", name)
+		}
+		*target = &amount
+	}
+
+	return filter, nil
+}
+
+// apply copies the provided fields onto the expense after validating them. A date is read
+// as a day in loc, keeping the time of day of the expense.
+func (input expenseInput) apply(expense *models.Expense, loc *time.Location) error {
+	if input.Description != nil {
+		if strings.TrimSpace(*input.Description) == "" {
+			return fmt.Errorf("description must not be empty")
+		}
+		expense.Description = strings.TrimSpace(*input.Description)
+	}
+	if input.Category != nil {
+		expense.Category = strings.TrimSpace(*input.Category)
+	}
+	if input.Merchant != nil {
+		expense.Merchant = strings.TrimSpace(*input.Merchant)
+	}
+	if input.Account != nil {
+		expense.Account = strings.TrimSpace(*input.Account)
+	}
+	if input.Tags != nil {
+		expense.Tags = models.NewTags(*input.Tags)
+	}
+	if input.Amount != nil {
+		if *input.Amount <= 0 {
+			return fmt.Errorf("amount must be greater than 0")
+		}
+		expense.Amount = *input.Amount
+	}
+	if input.Date != nil {
+		day, err := time.ParseInLocation("2006-01-02", *input.Date, loc)
+		if err != nil {
+			return fmt.Errorf("date must be in YYYY-MM-DD format")
+		}
+		clock := expense.Date.In(loc)
+		expense.Date = time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), loc)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+func TestExpenseInputApplyDate(t *testing.T) {
+	logged := time.Date(2026, 10, 19, 6, 30, 0, 0, testLocation)
+
+	tests := []struct {
+		name    string
+		date    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"same day", "2026-10-19", logged, false},
+		{"earlier day", "2026-10-01", time.Date(2026, 10, 1, 6, 30, 0, 0, testLocation), false},
+		{"wrong format", "19/10/2026", logged, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Stored in UTC, where 06:30 WIB is still the previous day
+			expense := models.Expense{Date: logged.UTC()}
+			date := tt.date
+			err := expenseInput{Date: &date}.apply(&expense, testLocation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("apply error = %v, want error %v", err, tt.wantErr)
+			}
+			if !expense.Date.Equal(tt.want) {
+				t.Errorf("date = %v, want %v", expense.Date.In(testLocation), tt.want)
+			}
+		})
+	}
+}
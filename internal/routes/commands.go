@@ -0,0 +1,208 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"SmartExpenseAI/internal/i18n"
+)
+
+// Command is a slash command of the bot. /start, /bantuan and the command menu of
+// Telegram clients are generated from the registered commands.
+type Command struct {
+	// Name is the command without the slash, lowercase as Telegram requires
+	Name string
+	// Aliases also run the command but are not listed anywhere
+	Aliases []string
+	// Args is the catalog key describing the arguments in /bantuan, such as "ID" or "[csv|xlsx]"
+	Args string
+	// Description is the catalog key of what the command does, in one line
+	Description string
+	// Example is the catalog key of the example shown after the description in /bantuan
+	Example string
+	// Hidden leaves the command out of /bantuan and the command menu
+	Hidden bool
+	Run    func(ctx context.Context, message *tgbotapi.Message)
+}
+
+// usage is the command with its arguments, as written in /bantuan
+func (c *Command) usage(p *i18n.Printer) string {
+	if c.Args == "" {
+		return "/" + c.Name
+	}
+	return "/" + c.Name + " " + p.T(c.Args)
+}
+
+// commandRegistry holds the commands in the order they are listed
+type commandRegistry struct {
+	commands []*Command
+	byName   map[string]*Command
+}
+
+func newCommandRegistry() *commandRegistry {
+	return &commandRegistry{byName: make(map[string]*Command)}
+}
+
+// register adds a command. A name or alias that is already taken is a programming error.
+func (r *commandRegistry) register(cmd *Command) {
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		if _, ok := r.byName[name]; ok {
+			panic(fmt.Sprintf("command %q registered twice", name))
+		}
+		r.byName[name] = cmd
+	}
+	r.commands = append(r.commands, cmd)
+}
+
+// lookup finds a command by name or alias, ignoring case
+func (r *commandRegistry) lookup(name string) (*Command, bool) {
+	cmd, ok := r.byName[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// listed returns the commands shown in /bantuan and the command menu
+func (r *commandRegistry) listed() []*Command {
+	var listed []*Command
+	for _, cmd := range r.commands {
+		if !cmd.Hidden {
+			listed = append(listed, cmd)
+		}
+	}
+	return listed
+}
+
+// chatCommand adapts a handler that only needs the chat to a Command.Run
+func chatCommand(run func(chatID int64)) func(context.Context, *tgbotapi.Message) {
+	return func(_ context.Context, message *tgbotapi.Message) {
+		run(message.Chat.ID)
+	}
+}
+
+// registerCommands sets up the slash commands in the order they are listed
+func (h *Handlers) registerCommands() {
+	for _, cmd := range []*Command{
+		{Name: "start", Hidden: true, Run: chatCommand(h.sendWelcome)},
+		{Name: "lihat", Aliases: []string{"list"}, Description: "command.lihat", Run: chatCommand(h.listExpenses)},
+		{Name: "minggu", Aliases: []string{"recap", "rekap"}, Description: "command.minggu", Run: chatCommand(h.sendWeeklyRecap)},
+		{Name: "bulan", Description: "command.bulan", Run: chatCommand(h.sendMonthlyRecap)},
+		{Name: "hapus", Aliases: []string{"delete"}, Args: "command.args.id", Description: "command.hapus",
+			Example: "command.hapus.example", Run: h.deleteCommand},
+		{Name: "update", Aliases: []string{"ubah", "edit"}, Args: "command.args.id", Description: "command.update",
+			Example: "command.update.example", Run: h.updateCommand},
+		{Name: "undo", Description: "command.undo", Run: chatCommand(h.undo)},
+		{Name: "batal", Aliases: []string{"cancel"}, Description: "command.batal", Run: chatCommand(h.cancelDialog)},
+		{Name: "riwayat", Aliases: []string{"history"}, Args: "command.args.id", Description: "command.riwayat",
+			Example: "command.riwayat.example", Run: h.historyCommand},
+		{Name: "pulihkan", Aliases: []string{"restore"}, Args: "command.args.id", Description: "command.pulihkan",
+			Example: "command.pulihkan.example", Run: h.restoreCommand},
+		{Name: "tanya", Aliases: []string{"ask"}, Args: "command.tanya.args", Description: "command.tanya",
+			Example: "command.tanya.example", Run: h.askCommand},
+		{Name: "cari", Aliases: []string{"search"}, Args: "command.cari.args", Description: "command.cari",
+			Example: "command.cari.example", Run: h.searchCommand},
+		{Name: "harian", Aliases: []string{"daily"}, Args: "command.harian.args", Description: "command.harian",
+			Example: "command.harian.example", Run: h.dailyCommand},
+		{Name: "anggaran", Aliases: []string{"budget"}, Args: "command.anggaran.args", Description: "command.anggaran",
+			Example: "command.anggaran.example", Run: h.budgetCommand},
+		{Name: "pengingat", Aliases: []string{"reminder"}, Args: "command.pengingat.args", Description: "command.pengingat",
+			Example: "command.pengingat.example", Run: h.reminderCommand},
+		{Name: "jadwal", Description: "command.jadwal", Run: chatCommand(h.sendJobStatus)},
+		{Name: "ekspor", Aliases: []string{"export"}, Args: "command.ekspor.args", Description: "command.ekspor",
+			Example: "command.ekspor.example", Run: h.exportCommand},
+		{Name: "impor", Aliases: []string{"import"}, Description: "command.impor", Run: h.importCommand},
+		{Name: "token", Args: "command.token.args", Description: "command.token", Run: h.tokenCommand},
+		{Name: "dashboard", Description: "command.dashboard", Run: chatCommand(h.sendDashboardLink)},
+		{Name: "bahasa", Aliases: []string{"language", "lang"}, Args: "command.bahasa.args", Description: "command.bahasa",
+			Example: "command.bahasa.example", Run: h.languageCommand},
+		{Name: "bantuan", Aliases: []string{"help"}, Description: "command.bantuan", Run: chatCommand(h.sendHelp)},
+	} {
+		h.commands.register(cmd)
+	}
+}
+
+// sendWelcome answers /start with a short list of the commands
+func (h *Handlers) sendWelcome(chatID int64) {
+	p := h.view.Printer(chatID)
+	var text strings.Builder
+	text.WriteString(p.T("welcome.intro") + "\n")
+	for _, cmd := range h.commands.listed() {
+		fmt.Fprintf(&text, "• /%s - %s\n", cmd.Name, p.T(cmd.Description))
+	}
+	h.view.Text(chatID, strings.TrimSuffix(text.String(), "\n"))
+}
+
+// sendHelp answers /bantuan with every command, its arguments and an example
+func (h *Handlers) sendHelp(chatID int64) {
+	p := h.view.Printer(chatID)
+	var text strings.Builder
+	text.WriteString(p.T("help.intro") + "\n")
+	for _, cmd := range h.commands.listed() {
+		fmt.Fprintf(&text, "• %s - %s", cmd.usage(p), p.T(cmd.Description))
+		if cmd.Example != "" {
+			text.WriteString(" " + p.T("help.example", p.T(cmd.Example)))
+		}
+		text.WriteString("\n")
+	}
+	h.view.Text(chatID, strings.TrimSuffix(text.String(), "\n"))
+}
+
+// telegramDescriptionLimit is the longest command description setMyCommands accepts
+const telegramDescriptionLimit = 256
+
+// SyncCommands publishes the listed commands as the command menu of Telegram clients, with
+// descriptions in each supported language. Clients in other languages see i18n.Default.
+func (h *Handlers) SyncCommands() error {
+	for _, lang := range i18n.Supported {
+		params := url.Values{}
+		if lang != i18n.Default {
+			params.Set("language_code", string(lang))
+		}
+		if err := h.setCommands(params, lang); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncChatCommands shows the command menu of one chat in the language it chose
+func (h *Handlers) syncChatCommands(chatID int64, lang i18n.Lang) error {
+	scope, err := json.Marshal(map[string]any{"type": "chat", "chat_id": chatID})
+	if err != nil {
+		return err
+	}
+	params := url.Values{}
+	params.Set("scope", string(scope))
+	return h.setCommands(params, lang)
+}
+
+// setCommands sends the command menu in lang with setMyCommands
+func (h *Handlers) setCommands(params url.Values, lang i18n.Lang) error {
+	type botCommand struct {
+		Command     string `json:"command"`
+		Description string `json:"description"`
+	}
+
+	p := i18n.NewPrinter(lang, h.location)
+	var menu []botCommand
+	for _, cmd := range h.commands.listed() {
+		description := p.T(cmd.Description)
+		if runes := []rune(description); len(runes) > telegramDescriptionLimit {
+			description = string(runes[:telegramDescriptionLimit-1]) + "…"
+		}
+		menu = append(menu, botCommand{Command: cmd.Name, Description: description})
+	}
+
+	data, err := json.Marshal(menu)
+	if err != nil {
+		return err
+	}
+	params.Set("commands", string(data))
+	if _, err := h.bot.MakeRequest("setMyCommands", params); err != nil {
+		return fmt.Errorf("failed to set bot commands in %s: %w", lang, err)
+	}
+	return nil
+}
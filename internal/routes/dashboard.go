@@ -0,0 +1,394 @@
+package routes
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"html/template"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"SmartExpenseAI/internal/i18n"
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+	"SmartExpenseAI/internal/services"
+)
+
+const (
+	sessionCookie     = "sei_session"
+	dashboardPageSize = 25
+	chartMaxHeight    = 100
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+var dashboardTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"amount": func(amount float64) string { return strconv.FormatFloat(amount, 'f', -1, 64) },
+	"mul":    func(a int, b int) int { return a * b },
+	"sub":    func(a int, b int) int { return a - b },
+}).ParseFS(templateFiles, "templates/*.html"))
+
+// chartBar is one bar of a dashboard chart, Percent is relative to the largest bar
+type chartBar struct {
+	Label   string
+	Value   float64
+	Percent int
+}
+
+// dashboardFilter holds the raw filter values so the form can be re-filled
+type dashboardFilter struct {
+	From     string
+	To       string
+	Category string
+	Query    string
+}
+
+type dashboardPage struct {
+	// Printer writes amounts and dates in the language the user chose in the bot
+	Printer      *i18n.Printer
+	CSRF         string
+	Filter       dashboardFilter
+	Categories   []repository.CategoryTotal
+	CategoryBars []chartBar
+	DailyBars    []chartBar
+	ChartWidth   int
+	Total        float64
+	Count        int64
+	Expenses     []models.Expense
+	Page         int
+	Pages        int
+	PrevURL      string
+	NextURL      string
+	ReturnURL    string
+}
+
+func (h *Handlers) DashboardRoutes(app *fiber.App) {
+	app.Get("/dashboard/login", h.dashboardLoginHandler)
+	app.Get("/dashboard/auth/telegram", h.dashboardTelegramAuthHandler)
+
+	dashboard := app.Group("/dashboard", h.requireWebSession)
+	dashboard.Get("/", h.dashboardHandler)
+	dashboard.Post("/expenses/:id", h.dashboardUpdateExpenseHandler)
+	dashboard.Post("/expenses/:id/delete", h.dashboardDeleteExpenseHandler)
+	dashboard.Post("/logout", h.dashboardLogoutHandler)
+}
+
+// requireWebSession redirects to the login page unless a valid session cookie is present,
+// and checks the CSRF token of form posts
+func (h *Handlers) requireWebSession(c *fiber.Ctx) error {
+	session, err := h.service.GetWebSession(c.Cookies(sessionCookie))
+	if err != nil {
+		return c.Redirect("/dashboard/login")
+	}
+
+	if c.Method() == fiber.MethodPost && c.FormValue("csrf") != session.CSRFToken {
+		return c.Status(403).SendString(h.webPrinter(session.UserID).T("web.error.csrf"))
+	}
+
+	c.Locals("userID", session.UserID)
+	c.Locals("csrf", session.CSRFToken)
+	return c.Next()
+}
+
+func (h *Handlers) dashboardLoginHandler(c *fiber.Ctx) error {
+	// One-time link sent by the bot
+	if token := c.Query("token"); token != "" {
+		userID, err := h.service.RedeemLoginToken(token)
+		if err != nil {
+			return h.renderLogin(c, "web.login.invalid_link")
+		}
+		return h.startSession(c, userID)
+	}
+
+	return h.renderLogin(c, "")
+}
+
+func (h *Handlers) dashboardTelegramAuthHandler(c *fiber.Ctx) error {
+	params := make(map[string]string)
+	c.Context().QueryArgs().VisitAll(func(key []byte, value []byte) {
+		params[string(key)] = string(value)
+	})
+
+	userID, err := services.VerifyTelegramLogin(params, h.bot.Token)
+	if err != nil {
+		log.Printf("Rejected Telegram login: %v", err)
+		return h.renderLogin(c, "web.login.verify_failed")
+	}
+	if userID != h.allowedUserID {
+		return h.renderLogin(c, "web.login.not_allowed")
+	}
+
+	return h.startSession(c, uint(userID))
+}
+
+func (h *Handlers) startSession(c *fiber.Ctx, userID uint) error {
+	raw, err := h.service.StartWebSession(userID)
+	if err != nil {
+		log.Printf("Error creating web session: %v", err)
+		return h.renderLogin(c, "web.login.session_failed")
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     sessionCookie,
+		Value:    raw,
+		Path:     "/dashboard",
+		Expires:  time.Now().Add(services.WebSessionTTL),
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https" || strings.HasPrefix(h.service.PublicURL(), "https://"),
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.Redirect("/dashboard")
+}
+
+func (h *Handlers) dashboardLogoutHandler(c *fiber.Ctx) error {
+	if err := h.service.EndWebSession(c.Cookies(sessionCookie)); err != nil {
+		log.Printf("Error ending web session: %v", err)
+	}
+	c.ClearCookie(sessionCookie)
+	return c.Redirect("/dashboard/login")
+}
+
+func (h *Handlers) dashboardHandler(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uint)
+	printer := h.webPrinter(userID)
+
+	raw := dashboardFilter{
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		Category: c.Query("category"),
+		Query:    c.Query("q"),
+	}
+	// Default to the last 30 days
+	if raw.From == "" && raw.To == "" {
+		raw.From = time.Now().In(h.location).AddDate(0, 0, -30).Format("2006-01-02")
+	}
+
+	filter := repository.ExpenseFilter{Category: raw.Category, Query: raw.Query}
+	var err error
+	if filter.From, err = parseExportDate(raw.From, false, h.location); err != nil {
+		return c.Status(400).SendString(printer.T("web.error.from"))
+	}
+	if filter.To, err = parseExportDate(raw.To, true, h.location); err != nil {
+		return c.Status(400).SendString(printer.T("web.error.to"))
+	}
+
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+
+	expenses, total, err := h.service.ListExpensesPage(userID, filter, "date", true, dashboardPageSize, (page-1)*dashboardPageSize)
+	if err != nil {
+		log.Printf("Error listing expenses: %v", err)
+		return c.Status(500).SendString(printer.T("web.error.load"))
+	}
+
+	categoryTotals, err := h.service.CategoryTotals(userID, filter)
+	if err != nil {
+		log.Printf("Error fetching category totals: %v", err)
+		return c.Status(500).SendString(printer.T("web.error.load"))
+	}
+
+	// All categories in the period, for the filter dropdown
+	allCategories, err := h.service.CategoryTotals(userID, repository.ExpenseFilter{From: filter.From, To: filter.To})
+	if err != nil {
+		log.Printf("Error fetching categories: %v", err)
+		return c.Status(500).SendString(printer.T("web.error.load"))
+	}
+
+	filtered, err := h.service.ListExpenses(userID, filter)
+	if err != nil {
+		log.Printf("Error fetching expenses: %v", err)
+		return c.Status(500).SendString(printer.T("web.error.load"))
+	}
+
+	data := dashboardPage{
+		Printer:    printer,
+		CSRF:       c.Locals("csrf").(string),
+		Filter:     raw,
+		Categories: allCategories,
+		Expenses:   expenses,
+		Count:      total,
+		Page:       page,
+		Pages:      int((total + dashboardPageSize - 1) / dashboardPageSize),
+	}
+	if data.Pages == 0 {
+		data.Pages = 1
+	}
+
+	var categoryBars []chartBar
+	for _, category := range categoryTotals {
+		data.Total += category.Total
+		categoryBars = append(categoryBars, chartBar{Label: category.Category, Value: category.Total})
+	}
+	data.CategoryBars = scaleBars(categoryBars)
+	data.DailyBars = scaleBars(dailyTotals(filtered, printer, h.location))
+	data.ChartWidth = len(data.DailyBars) * 12
+
+	query := url.Values{}
+	for key, value := range map[string]string{"from": raw.From, "to": raw.To, "category": raw.Category, "q": raw.Query} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	pageURL := func(p int) string {
+		query.Set("page", strconv.Itoa(p))
+		return "/dashboard?" + query.Encode()
+	}
+	data.ReturnURL = pageURL(page)
+	if page > 1 {
+		data.PrevURL = pageURL(page - 1)
+	}
+	if page < data.Pages {
+		data.NextURL = pageURL(page + 1)
+	}
+
+	return renderTemplate(c, printer, "dashboard.html", data)
+}
+
+func (h *Handlers) dashboardUpdateExpenseHandler(c *fiber.Ctx) error {
+	printer := h.webPrinter(c.Locals("userID").(uint))
+	expense, err := h.loadDashboardExpense(c)
+	if err != nil {
+		return c.Status(404).SendString(printer.T("web.error.not_found"))
+	}
+
+	description := strings.TrimSpace(c.FormValue("description"))
+	amount, amountErr := strconv.ParseFloat(c.FormValue("amount"), 64)
+	day, dateErr := time.ParseInLocation("2006-01-02", c.FormValue("date"), h.location)
+	if description == "" || amountErr != nil || amount <= 0 || dateErr != nil {
+		return c.Status(400).SendString(printer.T("web.error.invalid_expense"))
+	}
+
+	expense.Description = description
+	expense.Category = strings.TrimSpace(c.FormValue("category"))
+	expense.Amount = amount
+	// The form only edits the day, so the time the expense was logged at is kept
+	clock := expense.Date.In(h.location)
+	expense.Date = time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), h.location)
+
+	if err := h.service.UpdateExpense(expense); err != nil {
+		log.Printf("Error updating expense: %v", err)
+		return c.Status(500).SendString(printer.T("web.error.update"))
+	}
+
+	return c.Redirect(dashboardReturnURL(c))
+}
+
+func (h *Handlers) dashboardDeleteExpenseHandler(c *fiber.Ctx) error {
+	printer := h.webPrinter(c.Locals("userID").(uint))
+	expense, err := h.loadDashboardExpense(c)
+	if err != nil {
+		return c.Status(404).SendString(printer.T("web.error.not_found"))
+	}
+
+	if err := h.service.DeleteExpense(expense.UserID, expense.ID); err != nil {
+		log.Printf("Error deleting expense: %v", err)
+		return c.Status(500).SendString(printer.T("web.error.delete"))
+	}
+
+	return c.Redirect(dashboardReturnURL(c))
+}
+
+func (h *Handlers) loadDashboardExpense(c *fiber.Ctx) (*models.Expense, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	expense, err := h.service.GetExpense(c.Locals("userID").(uint), uint(id))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("Error fetching expense: %v", err)
+	}
+	return expense, err
+}
+
+// dashboardReturnURL only allows redirects back into the dashboard
+func dashboardReturnURL(c *fiber.Ctx) string {
+	returnURL := c.FormValue("return")
+	if !strings.HasPrefix(returnURL, "/dashboard") || strings.HasPrefix(returnURL, "//") {
+		return "/dashboard"
+	}
+	return returnURL
+}
+
+// renderLogin shows the login page with the message of errorKey, if any. Nobody is signed in
+// yet, so the page is in the language of the user the bot is allowed for.
+func (h *Handlers) renderLogin(c *fiber.Ctx, errorKey string) error {
+	printer := h.webPrinter(uint(h.allowedUserID))
+	return renderTemplate(c, printer, "login.html", fiber.Map{
+		"Printer":     printer,
+		"Error":       printer.T(errorKey),
+		"BotUsername": h.bot.Self.UserName,
+		"AuthURL":     h.service.PublicURL() + "/dashboard/auth/telegram",
+	})
+}
+
+// webPrinter returns the printer for the dashboard pages of a user, in the language they chose in the bot
+func (h *Handlers) webPrinter(userID uint) *i18n.Printer {
+	return i18n.NewPrinter(h.Language(int64(userID)), h.location)
+}
+
+func renderTemplate(c *fiber.Ctx, printer *i18n.Printer, name string, data interface{}) error {
+	var buf bytes.Buffer
+	if err := dashboardTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("Error rendering %s: %v", name, err)
+		return c.Status(500).SendString(printer.T("web.error.render"))
+	}
+
+	c.Type("html", "utf-8")
+	return c.Send(buf.Bytes())
+}
+
+// dailyTotals sums expenses per calendar day, oldest first, including days without expenses
+func dailyTotals(expenses []models.Expense, printer *i18n.Printer, loc *time.Location) []chartBar {
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	day := func(t time.Time) time.Time {
+		local := t.In(loc)
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	}
+
+	totals := make(map[time.Time]float64)
+	first, last := day(expenses[0].Date), day(expenses[0].Date)
+	for _, expense := range expenses {
+		d := day(expense.Date)
+		totals[d] += expense.Amount
+		if d.Before(first) {
+			first = d
+		}
+		if d.After(last) {
+			last = d
+		}
+	}
+
+	var bars []chartBar
+	for d := first; !d.After(last) && len(bars) < 366; d = d.AddDate(0, 0, 1) {
+		bars = append(bars, chartBar{Label: printer.DayMonth(d), Value: totals[d]})
+	}
+	return bars
+}
+
+// scaleBars sets each bar's Percent relative to the largest value
+func scaleBars(bars []chartBar) []chartBar {
+	max := 0.0
+	for _, bar := range bars {
+		if bar.Value > max {
+			max = bar.Value
+		}
+	}
+	for i := range bars {
+		if max > 0 {
+			bars[i].Percent = int(bars[i].Value / max * chartMaxHeight)
+		}
+	}
+	return bars
+}
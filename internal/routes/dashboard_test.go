@@ -0,0 +1,153 @@
+package routes
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"SmartExpenseAI/internal/i18n"
+	"SmartExpenseAI/internal/models"
+)
+
+// dashboardSession starts a web session for the test chat and returns its cookie and CSRF token
+func dashboardSession(t *testing.T, h *Handlers) (*http.Cookie, string) {
+	t.Helper()
+
+	raw, err := h.service.StartWebSession(uint(testChatID))
+	if err != nil {
+		t.Fatalf("StartWebSession: %v", err)
+	}
+	session, err := h.service.GetWebSession(raw)
+	if err != nil {
+		t.Fatalf("GetWebSession: %v", err)
+	}
+	return &http.Cookie{Name: sessionCookie, Value: raw}, session.CSRFToken
+}
+
+func TestDashboardEditKeepsLocalDay(t *testing.T) {
+	h, _, _ := newTestHandlers(t)
+	app := fiber.New()
+	h.DashboardRoutes(app)
+	cookie, csrf := dashboardSession(t, h)
+
+	// 06:30 WIB is still the previous day in UTC
+	logged := time.Date(2026, 10, 19, 6, 30, 0, 0, testLocation)
+	expense := &models.Expense{UserID: uint(testChatID), Description: "Kopi", Category: "Makanan", Amount: 25000, Date: logged.UTC()}
+	if err := h.service.CreateExpense(expense); err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/dashboard?from=2026-10-01&to=2026-10-31", nil)
+	req.AddCookie(cookie)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("GET /dashboard: %v", err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(page), `name="date" value="2026-10-19"`) {
+		t.Fatalf("dashboard does not show the expense on 2026-10-19:\n%s", page)
+	}
+
+	tests := []struct {
+		name string
+		date string
+		want time.Time
+	}{
+		{"amount only", "2026-10-19", logged},
+		{"new day", "2026-10-17", time.Date(2026, 10, 17, 6, 30, 0, 0, testLocation)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{
+				"csrf":        {csrf},
+				"description": {"Kopi"},
+				"category":    {"Makanan"},
+				"amount":      {"30000"},
+				"date":        {tt.date},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/dashboard/expenses/"+strconv.FormatUint(uint64(expense.ID), 10), strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.AddCookie(cookie)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("POST: %v", err)
+			}
+			if resp.StatusCode != http.StatusFound {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusFound)
+			}
+
+			saved, err := h.service.GetExpense(uint(testChatID), expense.ID)
+			if err != nil {
+				t.Fatalf("GetExpense: %v", err)
+			}
+			if !saved.Date.Equal(tt.want) {
+				t.Errorf("date = %v, want %v", saved.Date.In(testLocation), tt.want)
+			}
+			if saved.Amount != 30000 {
+				t.Errorf("amount = %v, want 30000", saved.Amount)
+			}
+		})
+	}
+}
+
+func TestDashboardPagesInUserLanguage(t *testing.T) {
+	tests := []struct {
+		lang     i18n.Lang
+		login    []string
+		overview []string
+	}{
+		{i18n.Indonesian,
+			[]string{`<html lang="id">`, "Masuk ke Dashboard", "Link login tidak valid"},
+			[]string{`<html lang="id">`, "Keluar", "<th>Tanggal</th>", "0 pengeluaran", "Halaman 1 dari 1"}},
+		{i18n.English,
+			[]string{`<html lang="en">`, "Sign in to the Dashboard", "The login link is invalid"},
+			[]string{`<html lang="en">`, "Sign out", "<th>Date</th>", "0 expenses", "Page 1 of 1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			h, _, _ := newTestHandlers(t)
+			if err := h.service.SetLanguage(uint(testChatID), tt.lang); err != nil {
+				t.Fatalf("SetLanguage: %v", err)
+			}
+			app := fiber.New()
+			h.DashboardRoutes(app)
+			cookie, _ := dashboardSession(t, h)
+
+			get := func(target string, cookie *http.Cookie) string {
+				t.Helper()
+				req := httptest.NewRequest(http.MethodGet, target, nil)
+				if cookie != nil {
+					req.AddCookie(cookie)
+				}
+				resp, err := app.Test(req)
+				if err != nil {
+					t.Fatalf("GET %s: %v", target, err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				return string(body)
+			}
+
+			login := get("/dashboard/login?token=unknown", nil)
+			for _, want := range tt.login {
+				if !strings.Contains(login, want) {
+					t.Errorf("login page does not contain %q", want)
+				}
+			}
+			overview := get("/dashboard", cookie)
+			for _, want := range tt.overview {
+				if !strings.Contains(overview, want) {
+					t.Errorf("dashboard does not contain %q", want)
+				}
+			}
+		})
+	}
+}
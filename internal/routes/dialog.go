@@ -0,0 +1,227 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/services"
+)
+
+// defaultDialogTimeout is how long a dialog waits for an answer unless it sets its own Timeout
+const defaultDialogTimeout = 10 * time.Minute
+
+// errNoConversation is returned by Dialog.Load when the chat is not in the dialog,
+// or its conversation has timed out
+var errNoConversation = errors.New("no conversation")
+
+// Conversation is one chat's progress through a dialog, with the data collected so far
+type Conversation[T any] struct {
+	ChatID int64
+	Step   string
+	Data   T
+
+	ended bool
+}
+
+// Goto moves the conversation to another step once the current step returns
+func (c *Conversation[T]) Goto(step string) {
+	c.Step = step
+}
+
+// End finishes the conversation once the current step returns
+func (c *Conversation[T]) End() {
+	c.ended = true
+}
+
+// StepFunc handles a message sent while a conversation is at its step. Without Goto or End the
+// conversation stays at the step, for example to ask again after an invalid answer. A returned
+// error ends the conversation.
+type StepFunc[T any] func(ctx context.Context, conv *Conversation[T], text string) error
+
+// Dialog is a multi-step conversation. Its state is kept in the database per chat, so it survives
+// restarts and is shared by all instances, and T is stored as JSON between steps.
+// Steps that wait for a button instead of a message need no StepFunc.
+type Dialog[T any] struct {
+	// Name identifies the dialog in the stored state and must be unique
+	Name string
+	// Title is the catalog key of the name of the dialog in the timeout and /batal messages
+	Title string
+	// Timeout is how long the dialog waits for an answer, defaultDialogTimeout when zero
+	Timeout time.Duration
+	Steps   map[string]StepFunc[T]
+
+	service *services.Service
+}
+
+// dialog is the type-independent part of Dialog used by the dispatch in Handlers
+type dialog interface {
+	name() string
+	title() string
+	// waitsForMessage reports whether the step has a StepFunc rather than waiting for a button
+	waitsForMessage(step string) bool
+	handle(ctx context.Context, state *models.ChatState, text string) error
+}
+
+// registerDialog makes a dialog receive the messages of chats that are in it
+func registerDialog[T any](h *Handlers, d *Dialog[T]) *Dialog[T] {
+	if _, ok := h.dialogs[d.Name]; ok {
+		panic(fmt.Sprintf("dialog %q registered twice", d.Name))
+	}
+	d.service = h.service
+	h.dialogs[d.Name] = d
+	return d
+}
+
+// Start puts a chat in the dialog at the given step, replacing any other dialog the chat was in
+func (d *Dialog[T]) Start(chatID int64, step string, data T) error {
+	return d.Save(&Conversation[T]{ChatID: chatID, Step: step, Data: data})
+}
+
+// Load returns the conversation of a chat, or errNoConversation when the chat is not in this dialog
+func (d *Dialog[T]) Load(chatID int64) (*Conversation[T], error) {
+	state, err := d.service.ChatState(chatID)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil || state.Flow != d.Name || time.Now().After(state.ExpiresAt) {
+		return nil, errNoConversation
+	}
+	return d.decode(state)
+}
+
+// Save stores the conversation and restarts its timeout, or ends it after End
+func (d *Dialog[T]) Save(conv *Conversation[T]) error {
+	if conv.ended {
+		return d.service.EndChatState(conv.ChatID)
+	}
+
+	data, err := json.Marshal(conv.Data)
+	if err != nil {
+		return err
+	}
+	timeout := d.Timeout
+	if timeout == 0 {
+		timeout = defaultDialogTimeout
+	}
+	return d.service.SaveChatState(&models.ChatState{
+		ChatID:    conv.ChatID,
+		Flow:      d.Name,
+		Step:      conv.Step,
+		Data:      string(data),
+		ExpiresAt: time.Now().Add(timeout),
+	})
+}
+
+func (d *Dialog[T]) name() string {
+	return d.Name
+}
+
+func (d *Dialog[T]) title() string {
+	return d.Title
+}
+
+func (d *Dialog[T]) waitsForMessage(step string) bool {
+	_, ok := d.Steps[step]
+	return ok
+}
+
+func (d *Dialog[T]) handle(ctx context.Context, state *models.ChatState, text string) error {
+	conv, err := d.decode(state)
+	if err == nil {
+		err = d.Steps[conv.Step](ctx, conv, text)
+	}
+	if err != nil {
+		d.service.EndChatState(state.ChatID)
+		return err
+	}
+	return d.Save(conv)
+}
+
+func (d *Dialog[T]) decode(state *models.ChatState) (*Conversation[T], error) {
+	conv := &Conversation[T]{ChatID: state.ChatID, Step: state.Step}
+	if err := json.Unmarshal([]byte(state.Data), &conv.Data); err != nil {
+		return nil, fmt.Errorf("decoding %s dialog state: %w", d.Name, err)
+	}
+	return conv, nil
+}
+
+// handleDialogMessage passes a plain-text message to the dialog the chat is in.
+// It reports whether the message was taken.
+func (h *Handlers) handleDialogMessage(ctx context.Context, message *tgbotapi.Message) bool {
+	chatID := message.Chat.ID
+	state, err := h.service.ChatState(chatID)
+	if err != nil {
+		log.Printf("Error reading chat state: %v", err)
+		return false
+	}
+	if state == nil {
+		return false
+	}
+
+	d, ok := h.dialogs[state.Flow]
+	if !ok {
+		// Left over by a dialog that no longer exists
+		h.service.EndChatState(chatID)
+		return false
+	}
+
+	expired := time.Now().After(state.ExpiresAt)
+	if !d.waitsForMessage(state.Step) {
+		// The dialog is waiting for a button, so the message is handled as usual
+		if expired {
+			h.service.EndChatState(chatID)
+		}
+		return false
+	}
+	if expired {
+		// The message was most likely meant as the late answer, so it is not logged as an expense
+		h.service.EndChatState(chatID)
+		p := h.view.Printer(chatID)
+		h.view.Text(chatID, p.T("dialog.timeout", p.T(d.title())))
+		return true
+	}
+
+	if err := d.handle(ctx, state, message.Text); err != nil {
+		log.Printf("Error in %s dialog: %v", d.name(), err)
+		p := h.view.Printer(chatID)
+		h.view.Text(chatID, p.T("dialog.failed", p.T(d.title())))
+	}
+	return true
+}
+
+// cancelDialog ends the dialog of a chat for /batal
+func (h *Handlers) cancelDialog(chatID int64) {
+	state, err := h.service.ChatState(chatID)
+	if err != nil {
+		log.Printf("Error reading chat state: %v", err)
+		h.view.Message(chatID, "dialog.cancel_failed")
+		return
+	}
+	if state == nil || time.Now().After(state.ExpiresAt) {
+		if state != nil {
+			h.service.EndChatState(chatID)
+		}
+		h.view.Message(chatID, "dialog.none")
+		return
+	}
+
+	if err := h.service.EndChatState(chatID); err != nil {
+		log.Printf("Error ending chat state: %v", err)
+		h.view.Message(chatID, "dialog.cancel_failed")
+		return
+	}
+
+	title := "dialog.title"
+	if d, ok := h.dialogs[state.Flow]; ok {
+		title = d.title()
+	}
+	p := h.view.Printer(chatID)
+	h.view.Text(chatID, p.T("dialog.cancelled", p.T(title)))
+}
@@ -0,0 +1,208 @@
+package routes
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"SmartExpenseAI/internal/i18n"
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/presenter"
+	"SmartExpenseAI/internal/repository"
+	"SmartExpenseAI/internal/services"
+)
+
+// allExpenses matches every expense
+var allExpenses = repository.ExpenseFilter{}
+
+// textUpdate is a message of the test chat; text starting with / is sent as a command
+func textUpdate(text string) tgbotapi.Update {
+	message := &tgbotapi.Message{
+		MessageID: 10,
+		From:      &tgbotapi.User{ID: int(testChatID), LanguageCode: "id"},
+		Chat:      &tgbotapi.Chat{ID: testChatID},
+		Text:      text,
+	}
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		message.Entities = &[]tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}}
+	}
+	return tgbotapi.Update{Message: message}
+}
+
+// buttonUpdate is a press of an inline button with the given callback data in the test chat
+func buttonUpdate(data string) tgbotapi.Update {
+	return tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "query",
+		From:    &tgbotapi.User{ID: int(testChatID), LanguageCode: "id"},
+		Message: &tgbotapi.Message{MessageID: 11, Chat: &tgbotapi.Chat{ID: testChatID}},
+		Data:    data,
+	}}
+}
+
+// startAmountEdit stores an expense and walks the guided edit up to the step waiting for its amount
+func startAmountEdit(t *testing.T, h *Handlers) *models.Expense {
+	t.Helper()
+
+	expense := &models.Expense{UserID: uint(testChatID), Description: "Kopi", Category: "Makanan", Amount: 25000, Date: time.Now()}
+	if err := h.service.CreateExpense(expense); err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+
+	h.ProcessUpdate(context.Background(), textUpdate("/ubah "+strconv.FormatUint(uint64(expense.ID), 10)))
+	assertDialogStep(t, h, editChoosing)
+	h.ProcessUpdate(context.Background(), buttonUpdate(presenter.EditFieldCallback+services.EditAmount))
+	assertDialogStep(t, h, services.EditAmount)
+	return expense
+}
+
+// assertDialogStep checks the step the test chat is at, or that it is in no dialog when step is empty
+func assertDialogStep(t *testing.T, h *Handlers, step string) {
+	t.Helper()
+
+	state, err := h.service.ChatState(testChatID)
+	if err != nil {
+		t.Fatalf("ChatState: %v", err)
+	}
+	switch {
+	case step == "" && state != nil:
+		t.Fatalf("chat is still in the %s dialog at step %q", state.Flow, state.Step)
+	case step != "" && state == nil:
+		t.Fatalf("chat is in no dialog, want step %q", step)
+	case step != "" && state.Step != step:
+		t.Fatalf("chat is at step %q, want %q", state.Step, step)
+	}
+}
+
+// assertAmount checks the stored amount of an expense
+func assertAmount(t *testing.T, h *Handlers, expenseID uint, want float64) {
+	t.Helper()
+
+	expense, err := h.service.GetExpense(uint(testChatID), expenseID)
+	if err != nil {
+		t.Fatalf("GetExpense: %v", err)
+	}
+	if expense.Amount != want {
+		t.Errorf("amount = %v, want %v", expense.Amount, want)
+	}
+}
+
+func TestEditDialogAppliesValue(t *testing.T) {
+	h, _, telegram := newTestHandlers(t)
+	p := i18n.NewPrinter(i18n.Indonesian, testLocation)
+	expense := startAmountEdit(t, h)
+
+	// An invalid answer asks again and keeps the step
+	h.ProcessUpdate(context.Background(), textUpdate("banyak"))
+	if got := telegram.lastText(); got != p.T("edit.invalid.amount") {
+		t.Errorf("reply to an invalid amount = %q", got)
+	}
+	assertDialogStep(t, h, services.EditAmount)
+	assertAmount(t, h, expense.ID, 25000)
+
+	h.ProcessUpdate(context.Background(), textUpdate("30rb"))
+	assertAmount(t, h, expense.ID, 30000)
+	assertDialogStep(t, h, editChoosing)
+
+	// While the dialog waits for a button, text is handled as usual and not taken as a value
+	handled, _ := h.service.ListExpenses(uint(testChatID), allExpenses)
+	h.ProcessUpdate(context.Background(), textUpdate("45000"))
+	assertAmount(t, h, expense.ID, 30000)
+	assertDialogStep(t, h, editChoosing)
+	if after, _ := h.service.ListExpenses(uint(testChatID), allExpenses); len(after) != len(handled) {
+		t.Errorf("text while waiting for a button was stored as an expense")
+	}
+
+	h.ProcessUpdate(context.Background(), buttonUpdate(presenter.EditDoneCallback))
+	assertDialogStep(t, h, "")
+}
+
+func TestDialogTimeout(t *testing.T) {
+	h, repo, telegram := newTestHandlers(t)
+	p := i18n.NewPrinter(i18n.Indonesian, testLocation)
+	expense := startAmountEdit(t, h)
+
+	state, err := repo.GetChatState(testChatID)
+	if err != nil || state == nil {
+		t.Fatalf("GetChatState: %v, %v", state, err)
+	}
+	state.ExpiresAt = time.Now().Add(-time.Second)
+	if err := repo.SaveChatState(state); err != nil {
+		t.Fatalf("SaveChatState: %v", err)
+	}
+
+	// The late answer ends the dialog instead of changing the expense or being logged as one
+	h.ProcessUpdate(context.Background(), textUpdate("30rb"))
+	if got, want := telegram.lastText(), p.T("dialog.timeout", p.T("edit.title")); got != want {
+		t.Errorf("reply after the timeout = %q, want %q", got, want)
+	}
+	assertDialogStep(t, h, "")
+	assertAmount(t, h, expense.ID, 25000)
+	if expenses, _ := h.service.ListExpenses(uint(testChatID), allExpenses); len(expenses) != 1 {
+		t.Errorf("%d expenses stored, want only the edited one", len(expenses))
+	}
+
+	// A button of the timed out dialog says it ended
+	h.ProcessUpdate(context.Background(), buttonUpdate(presenter.EditFieldCallback+services.EditAmount))
+	if got := telegram.lastText(); got != p.T("edit.expired") {
+		t.Errorf("reply to a button after the timeout = %q", got)
+	}
+}
+
+func TestCancelDialog(t *testing.T) {
+	h, _, telegram := newTestHandlers(t)
+	p := i18n.NewPrinter(i18n.Indonesian, testLocation)
+	expense := startAmountEdit(t, h)
+
+	h.ProcessUpdate(context.Background(), textUpdate("/batal"))
+	if got, want := telegram.lastText(), p.T("dialog.cancelled", p.T("edit.title")); got != want {
+		t.Errorf("reply to /batal = %q, want %q", got, want)
+	}
+	assertDialogStep(t, h, "")
+
+	// The value that would have been the answer is now an ordinary message
+	h.ProcessUpdate(context.Background(), textUpdate("30rb"))
+	assertAmount(t, h, expense.ID, 25000)
+
+	h.ProcessUpdate(context.Background(), textUpdate("/batal"))
+	if got := telegram.lastText(); got != p.T("dialog.none") {
+		t.Errorf("reply to /batal without a dialog = %q, want %q", got, p.T("dialog.none"))
+	}
+}
+
+func TestCommandDuringDialog(t *testing.T) {
+	h, _, telegram := newTestHandlers(t)
+	p := i18n.NewPrinter(i18n.Indonesian, testLocation)
+	expense := startAmountEdit(t, h)
+
+	// A command is run as usual rather than taken as the answer, and the dialog keeps waiting
+	h.ProcessUpdate(context.Background(), textUpdate("/hapus"))
+	if got := telegram.lastText(); got != p.T("delete.missing_id") {
+		t.Errorf("reply to /hapus during the dialog = %q, want %q", got, p.T("delete.missing_id"))
+	}
+	assertDialogStep(t, h, services.EditAmount)
+	assertAmount(t, h, expense.ID, 25000)
+
+	h.ProcessUpdate(context.Background(), textUpdate("30rb"))
+	assertAmount(t, h, expense.ID, 30000)
+
+	// Starting another edit replaces the conversation
+	other := &models.Expense{UserID: uint(testChatID), Description: "Parkir", Category: "Transport", Amount: 5000, Date: time.Now()}
+	if err := h.service.CreateExpense(other); err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	h.ProcessUpdate(context.Background(), buttonUpdate(presenter.EditFieldCallback+services.EditAmount))
+	h.ProcessUpdate(context.Background(), textUpdate("/ubah "+strconv.FormatUint(uint64(other.ID), 10)))
+	assertDialogStep(t, h, editChoosing)
+	conv, err := h.editDialog.Load(testChatID)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if conv.Data.ExpenseID != other.ID {
+		t.Errorf("edit is on expense %d, want %d", conv.Data.ExpenseID, other.ID)
+	}
+}
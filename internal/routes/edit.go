@@ -0,0 +1,139 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"SmartExpenseAI/internal/repository"
+	"SmartExpenseAI/internal/services"
+)
+
+// editData is what the guided edit keeps between steps
+type editData struct {
+	ExpenseID uint `json:"expense_id"`
+}
+
+// editChoosing is the guided edit step that waits for a field button. The other steps are
+// named after the field whose new value they wait for.
+const editChoosing = "choose"
+
+// newEditDialog registers the guided edit of an expense
+func (h *Handlers) newEditDialog() *Dialog[editData] {
+	steps := make(map[string]StepFunc[editData])
+	for _, field := range services.EditFields {
+		steps[field] = h.applyEditValue
+	}
+	return registerDialog(h, &Dialog[editData]{
+		Name:  "edit",
+		Title: "edit.title",
+		Steps: steps,
+	})
+}
+
+// startEdit opens the guided edit of an expense
+func (h *Handlers) startEdit(chatID int64, expenseID uint) {
+	expense, err := h.service.GetExpense(uint(chatID), expenseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.view.Message(chatID, "expense.not_found", expenseID)
+		return
+	}
+	if err == nil {
+		err = h.editDialog.Start(chatID, editChoosing, editData{ExpenseID: expenseID})
+	}
+	if err != nil {
+		log.Printf("Error starting guided edit: %v", err)
+		h.view.Message(chatID, "edit.failed", expenseID)
+		return
+	}
+	h.view.EditMenu(chatID, expense)
+}
+
+// selectEditField asks for the new value of a field, or shows the field buttons again when field is empty
+func (h *Handlers) selectEditField(chatID int64, messageID int, field string) {
+	conv, err := h.editDialog.Load(chatID)
+	if errors.Is(err, errNoConversation) {
+		h.view.EditMessage(chatID, messageID, "edit.expired")
+		return
+	}
+	if err != nil {
+		log.Printf("Error reading guided edit: %v", err)
+		return
+	}
+	if field != "" && !services.IsEditField(field) {
+		log.Printf("Unknown field to edit: %s", field)
+		return
+	}
+
+	expense, err := h.service.GetExpense(uint(chatID), conv.Data.ExpenseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		conv.End()
+		h.editDialog.Save(conv)
+		h.view.EditMessage(chatID, messageID, "expense.not_found", conv.Data.ExpenseID)
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching expense: %v", err)
+		return
+	}
+
+	conv.Goto(editChoosing)
+	if field != "" {
+		conv.Goto(field)
+	}
+	if err := h.editDialog.Save(conv); err != nil {
+		log.Printf("Error saving guided edit: %v", err)
+		return
+	}
+
+	if field == "" {
+		h.view.EditMenuInPlace(chatID, messageID, expense)
+		return
+	}
+	h.view.EditValuePrompt(chatID, messageID, field, expense)
+}
+
+// applyEditValue is the step that saves the value sent for the field the guided edit waits for
+func (h *Handlers) applyEditValue(ctx context.Context, conv *Conversation[editData], text string) error {
+	expense, err := h.service.EditExpenseField(uint(conv.ChatID), conv.Data.ExpenseID, conv.Step, text)
+	if errors.Is(err, services.ErrInvalidEditValue) {
+		h.view.EditInvalidValue(conv.ChatID, conv.Step)
+		return nil
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		conv.End()
+		h.view.Message(conv.ChatID, "expense.not_found", conv.Data.ExpenseID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	conv.Goto(editChoosing)
+	h.view.EditMenu(conv.ChatID, expense)
+	return nil
+}
+
+// finishEdit ends the guided edit and closes its menu
+func (h *Handlers) finishEdit(chatID int64, messageID int) {
+	conv, err := h.editDialog.Load(chatID)
+	if errors.Is(err, errNoConversation) {
+		h.view.EditMessage(chatID, messageID, "edit.ended")
+		return
+	}
+	if err != nil {
+		log.Printf("Error reading guided edit: %v", err)
+		return
+	}
+
+	conv.End()
+	if err := h.editDialog.Save(conv); err != nil {
+		log.Printf("Error finishing guided edit: %v", err)
+	}
+	expense, err := h.service.GetExpense(uint(chatID), conv.Data.ExpenseID)
+	if err != nil {
+		h.view.EditMessage(chatID, messageID, "edit.closed")
+		return
+	}
+	h.view.EditFinished(chatID, messageID, expense)
+}
@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"SmartExpenseAI/internal/export"
+	"SmartExpenseAI/internal/repository"
+)
+
+// parseExportArgs parses "/ekspor [csv|xlsx] [dari] [sampai] [kategori=X]"
+func parseExportArgs(args string, loc *time.Location) ([]string, repository.ExpenseFilter, error) {
+	var formats []string
+	var filter repository.ExpenseFilter
+	var dates []string
+
+	for _, token := range strings.Fields(args) {
+		lower := strings.ToLower(token)
+
+		if format, err := export.ParseFormat(lower); err == nil {
+			formats = append(formats, format)
+			continue
+		}
+
+		if strings.HasPrefix(lower, "kategori=") || strings.HasPrefix(lower, "kategori:") {
+			filter.Category = token[len("kategori="):]
+			continue
+		}
+
+		dates = append(dates, token)
+	}
+
+	if len(dates) > 2 {
+		return nil, filter, fmt.Errorf("too many dates")
+	}
+
+	var err error
+	if len(dates) > 0 {
+		if filter.From, err = parseExportDate(dates[0], false, loc); err != nil {
+			return nil, filter, err
+		}
+	}
+	if len(dates) > 1 {
+		if filter.To, err = parseExportDate(dates[1], true, loc); err != nil {
+			return nil, filter, err
+		}
+	}
+
+	// Without an explicit format, send both files
+	if len(formats) == 0 {
+		formats = []string{export.FormatCSV, export.FormatXLSX}
+	}
+
+	return formats, filter, nil
+}
+
+// parseExportDate parses a YYYY-MM-DD date; an end date is made inclusive
+func parseExportDate(value string, end bool, loc *time.Location) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return nil, err
+	}
+	if end {
+		date = date.AddDate(0, 0, 1)
+	}
+	return &date, nil
+}
@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/export"
+	"SmartExpenseAI/internal/i18n"
+)
+
+func TestParseExportArgs(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, testLocation)
+	// The end date includes the whole day
+	to := time.Date(2026, 11, 1, 0, 0, 0, 0, testLocation)
+	both := []string{export.FormatCSV, export.FormatXLSX}
+
+	tests := []struct {
+		args     string
+		formats  []string
+		from, to *time.Time
+		category string
+	}{
+		{args: "", formats: both},
+		{args: "csv", formats: []string{export.FormatCSV}},
+		{args: "Excel", formats: []string{export.FormatXLSX}},
+		{args: "xlsx csv", formats: []string{export.FormatXLSX, export.FormatCSV}},
+		{args: "2026-10-01", formats: both, from: &from},
+		{args: "csv 2026-10-01 2026-10-31", formats: []string{export.FormatCSV}, from: &from, to: &to},
+		{args: "kategori=Makanan", formats: both, category: "Makanan"},
+		{args: "Kategori:Transport xlsx", formats: []string{export.FormatXLSX}, category: "Transport"},
+		{args: "2026-10-01 2026-10-31 kategori=Makanan", formats: both, from: &from, to: &to, category: "Makanan"},
+	}
+
+	for _, tt := range tests {
+		formats, filter, err := parseExportArgs(tt.args, testLocation)
+		if err != nil {
+			t.Errorf("parseExportArgs(%q): %v", tt.args, err)
+			continue
+		}
+		if !slices.Equal(formats, tt.formats) {
+			t.Errorf("parseExportArgs(%q) formats = %v, want %v", tt.args, formats, tt.formats)
+		}
+		if !equalDate(filter.From, tt.from) || !equalDate(filter.To, tt.to) {
+			t.Errorf("parseExportArgs(%q) from, to = %v, %v, want %v, %v", tt.args, filter.From, filter.To, tt.from, tt.to)
+		}
+		if filter.Category != tt.category {
+			t.Errorf("parseExportArgs(%q) category = %q, want %q", tt.args, filter.Category, tt.category)
+		}
+	}
+}
+
+func TestParseExportArgsRejects(t *testing.T) {
+	for _, args := range []string{
+		"pdf",
+		"01/10/2026",
+		"2026-10-01 2026-10-32",
+		"2026-10-01 2026-10-15 2026-10-31",
+	} {
+		if _, _, err := parseExportArgs(args, testLocation); err == nil {
+			t.Errorf("parseExportArgs(%q) succeeded, want an error", args)
+		}
+	}
+}
+
+func TestExportCommandInvalidArgs(t *testing.T) {
+	h, _, telegram := newTestHandlers(t)
+	p := i18n.NewPrinter(i18n.Indonesian, testLocation)
+
+	h.ProcessUpdate(context.Background(), textUpdate("/ekspor pdf"))
+	if got := telegram.lastText(); got != p.T("export.invalid") {
+		t.Errorf("reply = %q, want the export usage", got)
+	}
+}
@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"SmartExpenseAI/internal/config"
+	"SmartExpenseAI/internal/dispatcher"
+	"SmartExpenseAI/internal/presenter"
+	"SmartExpenseAI/internal/services"
+)
+
+// Handlers holds the dependencies of the HTTP routes and the Telegram update handlers
+type Handlers struct {
+	bot           *tgbotapi.BotAPI
+	allowedUserID int64
+	webhookSecret string
+	adminToken    string
+	location      *time.Location
+
+	service    *services.Service
+	scheduler  *services.Scheduler
+	view       *presenter.Telegram
+	dispatcher *dispatcher.Dispatcher
+
+	// commands are the registered slash commands
+	commands *commandRegistry
+	// dialogs are the registered multi-step conversations by name
+	dialogs    map[string]dialog
+	editDialog *Dialog[editData]
+
+	// shuttingDown makes the readiness check fail while the server drains
+	shuttingDown atomic.Bool
+}
+
+func New(bot *tgbotapi.BotAPI, cfg *config.Config, service *services.Service, scheduler *services.Scheduler, view *presenter.Telegram) *Handlers {
+	h := &Handlers{
+		bot:           bot,
+		allowedUserID: cfg.Telegram.UserID,
+		webhookSecret: cfg.Telegram.WebhookSecret,
+		adminToken:    cfg.Server.AdminToken,
+		location:      cfg.Location,
+		service:       service,
+		scheduler:     scheduler,
+		view:          view,
+		commands:      newCommandRegistry(),
+		dialogs:       make(map[string]dialog),
+	}
+	h.registerCommands()
+	view.SetLanguages(h)
+	h.editDialog = h.newEditDialog()
+	return h
+}
+
+// SetDispatcher sets the worker pool that ReceiveUpdate queues updates on. The dispatcher
+// is created after the handlers since it runs ProcessUpdate.
+func (h *Handlers) SetDispatcher(d *dispatcher.Dispatcher) {
+	h.dispatcher = d
+}
@@ -0,0 +1,99 @@
+package routes
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"SmartExpenseAI/internal/config"
+	"SmartExpenseAI/internal/presenter"
+	"SmartExpenseAI/internal/repository/memory"
+	"SmartExpenseAI/internal/services"
+)
+
+// testLocation stands in for Asia/Jakarta without depending on the tz database
+var testLocation = time.FixedZone("WIB", 7*60*60)
+
+const testChatID int64 = 42
+
+// fakeTelegram records the Bot API calls made by the handlers and answers each one successfully
+type fakeTelegram struct {
+	mu    sync.Mutex
+	calls []telegramCall
+}
+
+type telegramCall struct {
+	Method string
+	Params url.Values
+}
+
+func (f *fakeTelegram) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+	method := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+
+	f.mu.Lock()
+	f.calls = append(f.calls, telegramCall{Method: method, Params: req.PostForm})
+	f.mu.Unlock()
+
+	body := `{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":42,"type":"private"}}}`
+	if method == "answerCallbackQuery" {
+		body = `{"ok":true,"result":true}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// texts returns the text of every message sent or edited so far
+func (f *fakeTelegram) texts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var texts []string
+	for _, call := range f.calls {
+		if text := call.Params.Get("text"); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+// lastText returns the text of the latest message sent or edited
+func (f *fakeTelegram) lastText() string {
+	texts := f.texts()
+	if len(texts) == 0 {
+		return ""
+	}
+	return texts[len(texts)-1]
+}
+
+// newTestHandlers returns handlers on an empty in-memory repository, without an AI provider,
+// whose Telegram replies are recorded instead of sent
+func newTestHandlers(t *testing.T) (*Handlers, *memory.Store, *fakeTelegram) {
+	t.Helper()
+
+	telegram := &fakeTelegram{}
+	bot := &tgbotapi.BotAPI{
+		Token:  "test-token",
+		Client: &http.Client{Transport: telegram},
+		Self:   tgbotapi.User{ID: 1, UserName: "test_bot"},
+	}
+
+	repo := memory.New()
+	cfg := &config.Config{Location: testLocation}
+	cfg.Telegram.UserID = testChatID
+	service := services.New(repo, services.NewOpenRouter(config.AIConfig{}), cfg)
+	scheduler := services.NewScheduler(repo, testLocation, "test")
+	return New(bot, cfg, service, scheduler, presenter.NewTelegram(bot, testLocation)), repo, telegram
+}
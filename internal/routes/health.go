@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// healthCheckTimeout bounds the database ping of the readiness check
+const healthCheckTimeout = 2 * time.Second
+
+// SetShuttingDown marks the instance as draining so load balancers stop routing to it
+func (h *Handlers) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+func (h *Handlers) HealthRoutes(app *fiber.App) {
+	// Liveness: the process is up and serving requests
+	app.Get("/healthz", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"status": "ok"})
+	})
+
+	// Readiness: the instance can handle updates. Commands keep working without the AI, so a
+	// missing AI only reports the instance as degraded.
+	app.Get("/readyz", func(c *fiber.Ctx) error {
+		checks := fiber.Map{
+			"database":  "ok",
+			"ai":        "ok",
+			"accepting": "ok",
+		}
+		ready, degraded := true, false
+
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		defer cancel()
+		if err := h.service.Ping(ctx); err != nil {
+			checks["database"] = "unreachable"
+			ready = false
+		}
+
+		if !h.service.AIConfigured() {
+			checks["ai"] = "not configured"
+			degraded = true
+		}
+
+		if h.shuttingDown.Load() {
+			checks["accepting"] = "shutting down"
+			ready = false
+		}
+
+		if !ready {
+			return c.Status(503).JSON(fiber.Map{
+				"status": "unavailable",
+				"checks": checks,
+			})
+		}
+
+		status := "ok"
+		if degraded {
+			status = "degraded"
+		}
+		return c.JSON(fiber.Map{
+			"status": status,
+			"checks": checks,
+		})
+	})
+}
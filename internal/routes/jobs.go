@@ -0,0 +1,149 @@
+package routes
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/services"
+)
+
+func (h *Handlers) JobRoutes(app *fiber.App) {
+	// Status of the persisted scheduled jobs
+	app.Get("/jobs", h.requireAdminToken, func(c *fiber.Ctx) error {
+		statuses, err := h.scheduler.Statuses()
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"error": "Failed to load job status",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"instance": h.scheduler.InstanceID(),
+			"jobs":     statuses,
+		})
+	})
+}
+
+// ScheduleJobs registers the weekly recap, the inactivity reminder, the cleanups
+// and the daily digests stored in user preferences on the scheduler
+func (h *Handlers) ScheduleJobs() {
+	// Weekly recap runs every Sunday at 8:00 AM in the configured timezone (it used to be UTC)
+	if err := h.scheduler.Schedule("weekly-recap", "0 8 * * 0", func() {
+		h.sendWeeklyRecap(h.allowedUserID)
+	}); err != nil {
+		log.Printf("Error scheduling weekly recap: %v", err)
+	}
+
+	// Inactivity reminders are checked every evening at 8:00 PM
+	if err := h.scheduler.Schedule("inactivity-reminder", "0 20 * * *", h.sendInactivityReminders); err != nil {
+		log.Printf("Error scheduling inactivity reminder: %v", err)
+	}
+
+	// Forget processed Telegram update IDs once retries are no longer possible
+	if err := h.scheduler.Schedule("processed-updates-cleanup", "30 3 * * *", func() {
+		deleted, err := h.service.CleanupProcessedUpdates()
+		if err != nil {
+			log.Printf("Error cleaning up processed updates: %v", err)
+			return
+		}
+		log.Printf("Cleaned up %d processed updates", deleted)
+	}); err != nil {
+		log.Printf("Error scheduling processed updates cleanup: %v", err)
+	}
+
+	// Remove expired dashboard login links and sessions
+	if err := h.scheduler.Schedule("dashboard-login-cleanup", "45 3 * * *", func() {
+		if err := h.service.CleanupExpiredLogins(); err != nil {
+			log.Printf("Error cleaning up dashboard logins: %v", err)
+		}
+	}); err != nil {
+		log.Printf("Error scheduling dashboard login cleanup: %v", err)
+	}
+
+	// Remove imports and duplicate questions nobody confirmed
+	if err := h.scheduler.Schedule("pending-confirmations-cleanup", "50 3 * * *", func() {
+		deleted, err := h.service.CleanupPendingConfirmations()
+		if err != nil {
+			log.Printf("Error cleaning up pending confirmations: %v", err)
+			return
+		}
+		log.Printf("Cleaned up %d pending confirmations", deleted)
+	}); err != nil {
+		log.Printf("Error scheduling pending confirmations cleanup: %v", err)
+	}
+
+	// Register a digest job for every user that enabled it
+	prefs, err := h.service.DigestPreferences()
+	if err != nil {
+		log.Printf("Error loading digest preferences: %v", err)
+	}
+	for i := range prefs {
+		h.scheduleDigest(&prefs[i])
+	}
+}
+
+// scheduleDigest replaces the digest job of a user with one matching their preferences
+func (h *Handlers) scheduleDigest(pref *models.UserPreference) {
+	name := fmt.Sprintf("daily-digest-%d", pref.UserID)
+
+	if !pref.DigestEnabled {
+		h.scheduler.Unschedule(name)
+		return
+	}
+
+	cronExpr, err := services.DailyCron(pref.DigestTime)
+	if err != nil {
+		log.Printf("Invalid digest time for user %d: %v", pref.UserID, err)
+		return
+	}
+
+	userID := pref.UserID
+	// A digest caught up after downtime covers the day it was missed, not the current one
+	if err := h.scheduler.ScheduleSlots(name, cronExpr, func(slot time.Time) {
+		h.sendDailyDigest(int64(userID), slot)
+	}); err != nil {
+		log.Printf("Error scheduling daily digest for user %d: %v", userID, err)
+	}
+}
+
+func (h *Handlers) sendWeeklyRecap(chatID int64) {
+	recap, err := h.service.WeeklyRecap(uint(chatID))
+	if err != nil {
+		log.Printf("Error fetching expenses: %v", err)
+		return
+	}
+	h.view.WeeklyRecap(chatID, recap)
+}
+
+// sendDailyDigest sends the digest of the day of the given time
+func (h *Handlers) sendDailyDigest(chatID int64, day time.Time) {
+	digest, err := h.service.DailyDigest(uint(chatID), day)
+	if err != nil {
+		log.Printf("Error building daily digest: %v", err)
+		return
+	}
+	h.view.DailyDigest(chatID, digest)
+}
+
+// sendInactivityReminders reminds users who have not logged an expense for their configured number of days
+func (h *Handlers) sendInactivityReminders() {
+	reminders, err := h.service.DueReminders()
+	if err != nil {
+		log.Printf("Error fetching reminders: %v", err)
+	}
+
+	for _, reminder := range reminders {
+		if err := h.view.InactivityReminder(reminder); err != nil {
+			log.Printf("Error sending reminder to user %d: %v", reminder.UserID, err)
+			continue
+		}
+
+		if err := h.service.MarkReminderSent(reminder.UserID, time.Now()); err != nil {
+			log.Printf("Error saving reminder timestamp for user %d: %v", reminder.UserID, err)
+		}
+	}
+}
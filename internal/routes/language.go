@@ -0,0 +1,66 @@
+package routes
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"SmartExpenseAI/internal/i18n"
+)
+
+// Language returns the language a chat reads, for the presenter
+func (h *Handlers) Language(chatID int64) i18n.Lang {
+	lang, err := h.service.Language(uint(chatID))
+	if err != nil {
+		log.Printf("Error reading language of chat %d: %v", chatID, err)
+	}
+	return lang
+}
+
+// detectLanguage sets the language of a chat from the Telegram client the first time it writes
+func (h *Handlers) detectLanguage(chatID int64, from *tgbotapi.User) {
+	if from == nil {
+		return
+	}
+	if err := h.service.DetectLanguage(uint(chatID), from.LanguageCode); err != nil {
+		log.Printf("Error detecting language of chat %d: %v", chatID, err)
+	}
+}
+
+// notAuthorized is the refusal shown to other Telegram users, in the language of their client
+func (h *Handlers) notAuthorized(from *tgbotapi.User) string {
+	lang := i18n.Default
+	if from != nil {
+		lang = i18n.Detect(from.LanguageCode)
+	}
+	return i18n.NewPrinter(lang, h.location).T("auth.denied")
+}
+
+// languageCommand shows the language of the chat, or changes it to the one given after /bahasa
+func (h *Handlers) languageCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		h.view.Language(chatID)
+		return
+	}
+
+	lang, ok := i18n.Parse(args)
+	if !ok {
+		h.view.Message(chatID, "language.invalid")
+		return
+	}
+	if err := h.service.SetLanguage(uint(chatID), lang); err != nil {
+		log.Printf("Error saving language: %v", err)
+		h.view.Message(chatID, "language.save_failed")
+		return
+	}
+
+	if err := h.syncChatCommands(chatID, lang); err != nil {
+		log.Printf("Error updating command menu of chat %d: %v", chatID, err)
+	}
+	h.view.LanguageSet(chatID)
+}
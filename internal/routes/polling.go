@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"context"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// pollingTimeout is the long-polling timeout in seconds passed to getUpdates
+const pollingTimeout = 60
+
+// StartPolling receives updates with getUpdates instead of the webhook and feeds them
+// to ReceiveUpdate, so the bot works without a public HTTPS URL. It blocks until the
+// update channel is closed.
+func (h *Handlers) StartPolling() {
+	// getUpdates is refused by Telegram while a webhook is set
+	if _, err := h.bot.RemoveWebhook(); err != nil {
+		log.Fatal("Failed to remove webhook before polling: ", err)
+	}
+
+	config := tgbotapi.NewUpdate(0)
+	config.Timeout = pollingTimeout
+
+	updates, err := h.bot.GetUpdatesChan(config)
+	if err != nil {
+		log.Fatal("Failed to start polling: ", err)
+	}
+
+	log.Printf("Polling for updates as @%s", h.bot.Self.UserName)
+	for update := range updates {
+		if err := h.ReceiveUpdate(context.Background(), update); err != nil {
+			log.Printf("Dropped update %d: %v", update.UpdateID, err)
+		}
+	}
+}
+
+// StopPolling stops requesting new updates; updates already received are still handled
+func (h *Handlers) StopPolling() {
+	h.bot.StopReceivingUpdates()
+}
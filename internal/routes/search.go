@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"SmartExpenseAI/internal/repository"
+)
+
+// parseSearchArgs parses "/cari [kata kunci] [kategori=X] [tag=X|#X] [min=N] [max=N] [dari=YYYY-MM-DD]
+// [sampai=YYYY-MM-DD] [hal=N]". It also returns the arguments without the page, to link other pages.
+func parseSearchArgs(args string, loc *time.Location) (repository.ExpenseFilter, int, string, error) {
+	var filter repository.ExpenseFilter
+	var words, kept []string
+	page := 1
+
+	for _, token := range strings.Fields(args) {
+		key, value, hasValue := strings.Cut(token, "=")
+		key = strings.ToLower(key)
+
+		switch {
+		case hasValue && key == "hal":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return filter, 0, "", fmt.Errorf("invalid page %q", value)
+			}
+			page = n
+			continue
+		case hasValue && key == "kategori":
+			filter.Category = value
+		case hasValue && key == "tag":
+			filter.Tag = value
+		case strings.HasPrefix(token, "#") && len(token) > 1:
+			filter.Tag = token[1:]
+		case hasValue && (key == "min" || key == "max"):
+			amount, err := strconv.ParseFloat(value, 64)
+			if err != nil || amount < 0 {
+				return filter, 0, "", fmt.Errorf("invalid amount %q", value)
+			}
+			if key == "min" {
+				filter.MinAmount = &amount
+			} else {
+				filter.MaxAmount = &amount
+			}
+		case hasValue && (key == "dari" || key == "sampai"):
+			date, err := parseExportDate(value, key == "sampai", loc)
+			if err != nil {
+				return filter, 0, "", err
+			}
+			if key == "dari" {
+				filter.From = date
+			} else {
+				filter.To = date
+			}
+		default:
+			words = append(words, token)
+		}
+		kept = append(kept, token)
+	}
+
+	filter.Query = strings.Join(words, " ")
+	return filter, page, strings.Join(kept, " "), nil
+}
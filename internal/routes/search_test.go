@@ -0,0 +1,99 @@
+package routes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/i18n"
+)
+
+func TestParseSearchArgs(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, testLocation)
+	// sampai includes the whole day
+	to := time.Date(2026, 10, 16, 0, 0, 0, 0, testLocation)
+
+	tests := []struct {
+		args     string
+		query    string
+		category string
+		tag      string
+		min, max float64
+		from, to *time.Time
+		page     int
+		kept     string
+	}{
+		{args: "", page: 1},
+		{args: "kopi susu", query: "kopi susu", page: 1, kept: "kopi susu"},
+		{args: "kopi kategori=Makanan", query: "kopi", category: "Makanan", page: 1, kept: "kopi kategori=Makanan"},
+		{args: "KATEGORI=Makanan", category: "Makanan", page: 1, kept: "KATEGORI=Makanan"},
+		{args: "#kantor", tag: "kantor", page: 1, kept: "#kantor"},
+		{args: "tag=liburan grab", query: "grab", tag: "liburan", page: 1, kept: "tag=liburan grab"},
+		{args: "min=10000 max=50000.5", min: 10000, max: 50000.5, page: 1, kept: "min=10000 max=50000.5"},
+		{args: "dari=2026-10-01 sampai=2026-10-15", from: &from, to: &to, page: 1, kept: "dari=2026-10-01 sampai=2026-10-15"},
+		// The page is left out of the kept arguments so other pages can be linked
+		{args: "parkir hal=3 min=2000", query: "parkir", min: 2000, page: 3, kept: "parkir min=2000"},
+		// A lone # and words with an unknown key are searched for
+		{args: "# a=b", query: "# a=b", page: 1, kept: "# a=b"},
+	}
+
+	for _, tt := range tests {
+		filter, page, kept, err := parseSearchArgs(tt.args, testLocation)
+		if err != nil {
+			t.Errorf("parseSearchArgs(%q): %v", tt.args, err)
+			continue
+		}
+		if filter.Query != tt.query || filter.Category != tt.category || filter.Tag != tt.tag {
+			t.Errorf("parseSearchArgs(%q) query, category, tag = %q, %q, %q, want %q, %q, %q",
+				tt.args, filter.Query, filter.Category, filter.Tag, tt.query, tt.category, tt.tag)
+		}
+		if !equalAmount(filter.MinAmount, tt.min) || !equalAmount(filter.MaxAmount, tt.max) {
+			t.Errorf("parseSearchArgs(%q) min, max = %v, %v, want %v, %v", tt.args, filter.MinAmount, filter.MaxAmount, tt.min, tt.max)
+		}
+		if !equalDate(filter.From, tt.from) || !equalDate(filter.To, tt.to) {
+			t.Errorf("parseSearchArgs(%q) from, to = %v, %v, want %v, %v", tt.args, filter.From, filter.To, tt.from, tt.to)
+		}
+		if page != tt.page || kept != tt.kept {
+			t.Errorf("parseSearchArgs(%q) page, kept = %d, %q, want %d, %q", tt.args, page, kept, tt.page, tt.kept)
+		}
+	}
+}
+
+func TestParseSearchArgsRejects(t *testing.T) {
+	for _, args := range []string{
+		"hal=0",
+		"hal=dua",
+		"min=-5",
+		"max=banyak",
+		"dari=01/10/2026",
+		"sampai=2026-13-01",
+	} {
+		if _, _, _, err := parseSearchArgs(args, testLocation); err == nil {
+			t.Errorf("parseSearchArgs(%q) succeeded, want an error", args)
+		}
+	}
+}
+
+func TestSearchCommandInvalidArgs(t *testing.T) {
+	h, _, telegram := newTestHandlers(t)
+	p := i18n.NewPrinter(i18n.Indonesian, testLocation)
+
+	h.ProcessUpdate(context.Background(), textUpdate("/cari kopi min=murah"))
+	if got := telegram.lastText(); got != p.T("search.invalid") {
+		t.Errorf("reply = %q, want the search usage", got)
+	}
+}
+
+func equalAmount(got *float64, want float64) bool {
+	if want == 0 {
+		return got == nil
+	}
+	return got != nil && *got == want
+}
+
+func equalDate(got, want *time.Time) bool {
+	if want == nil {
+		return got == nil
+	}
+	return got != nil && got.Equal(*want)
+}
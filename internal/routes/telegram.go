@@ -1,428 +1,880 @@
 package routes
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"regexp"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/gofiber/fiber/v2"
 
-	"SmartExpenseAI/internal/database"
+	"SmartExpenseAI/internal/importer"
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/presenter"
+	"SmartExpenseAI/internal/repository"
 	"SmartExpenseAI/internal/services"
 )
 
-// Global variables to hold bot and allowed user ID
-var (
-	bot           *tgbotapi.BotAPI
-	allowedUserID int64
-)
+// maxWebhookBodySize bounds the size of an update accepted on the webhook
+const maxWebhookBodySize = 1 << 20
 
-func InitTelegram(botInstance *tgbotapi.BotAPI, userID int64) {
-	bot = botInstance
-	allowedUserID = userID
-}
+// webhookQueueTimeout is how long the webhook waits for room in a worker queue
+const webhookQueueTimeout = 5 * time.Second
+
+func (h *Handlers) TelegramRoutes(app *fiber.App) {
+	if h.webhookSecret == "" {
+		log.Println("WEBHOOK_SECRET is not set, webhook requests are not verified")
+	}
 
-func TelegramRoutes(app *fiber.App) {
 	// Webhook endpoint for Telegram
 	app.Post("/webhook", func(c *fiber.Ctx) error {
+		// Only Telegram knows the secret registered with setWebhook
+		if h.webhookSecret != "" && !secretsEqual(c.Get("X-Telegram-Bot-Api-Secret-Token"), h.webhookSecret) {
+			log.Printf("Rejected webhook request with invalid secret token from %s", c.IP())
+			return c.Status(401).SendString("Unauthorized")
+		}
+
+		if len(c.Body()) > maxWebhookBodySize {
+			return c.Status(413).SendString("Payload Too Large")
+		}
+
 		var update tgbotapi.Update
-		if err := c.BodyParser(&update); err != nil {
+		if err := json.Unmarshal(c.Body(), &update); err != nil {
 			log.Printf("Failed to parse update: %v", err)
 			return c.Status(400).SendString("Bad Request")
 		}
+		if update.UpdateID <= 0 {
+			log.Printf("Rejected update without update_id")
+			return c.Status(400).SendString("Bad Request")
+		}
 
-		// Process text messages only
-		if update.Message != nil && update.Message.Text != "" {
-			// Check if user is authorized
-			if int64(update.Message.From.ID) != allowedUserID {
-				msg := tgbotapi.NewMessage(update.Message.Chat.ID, "You are not authorized to use this bot.")
-				bot.Send(msg)
-				return c.SendString("OK")
-			}
-
-			// Check if it's a command
-			if update.Message.IsCommand() {
-				command := update.Message.Command()
-				go handleCommand(bot, update.Message, command)
-			} else {
-				// Process as natural language expense (only for expense entries)
-				go func() {
-					text := update.Message.Text
-					chatID := update.Message.Chat.ID
-
-					log.Printf("Received text: %s", text)
-
-					// Parse the expense using AI (only for expense extraction)
-					expense, err := services.ParseExpense(text)
-					if err != nil {
-						log.Printf("Error parsing expense: %v", err)
-
-						// Instead of generic error message, provide helpful guidance
-						responseText := "🤖 Halo! Saya SmartExpenseAI, asisten yang membantu kamu mencatat pengeluaran.\n\n" +
-							"Kamu bisa kirim pesan seperti:\n" +
-							"• \"makan nasi padang 25000\"\n" +
-							"• \"beli buku 50k\"\n\n" +
-							"Untuk fitur lainnya, gunakan perintah:\n" +
-							"• /lihat - Lihat pengeluaran terakhir\n" +
-							"• /bulan - Rekap bulan ini\n" +
-							"• /hapus - Hapus pengeluaran"
-
-						msg := tgbotapi.NewMessage(chatID, responseText)
-						bot.Send(msg)
-						return
-					}
-
-					// Only save if there's actual expense data (amount > 0)
-					if expense.Amount <= 0 {
-						// No expense data found, provide helpful response
-						responseText := "🤖 Tidak bisa mengenali pengeluaran dari pesanmu.\n\n" +
-							"Contoh format yang benar:\n" +
-							"• \"makan nasi padang 25000\"\n" +
-							"• \"beli buku 50k\"\n\n" +
-							"Untuk fitur lainnya, gunakan perintah:\n" +
-							"• /lihat - Lihat pengeluaran terakhir\n" +
-							"• /bulan - Rekap bulan ini\n" +
-							"• /hapus - Hapus pengeluaran"
-
-						msg := tgbotapi.NewMessage(chatID, responseText)
-						bot.Send(msg)
-						return
-					}
-
-					log.Printf("Parsed expense: %+v", expense)
-
-					// Set the user ID for the expense
-					expense.UserID = uint(allowedUserID)
-
-					// Save to database
-					err = database.DB.Create(&expense).Error
-					if err != nil {
-						log.Printf("Error saving expense to database: %v", err)
-
-						msg := tgbotapi.NewMessage(chatID, "Error saving your expense. Please try again.")
-						bot.Send(msg)
-						return
-					}
-
-					log.Printf("Saved expense to database")
-
-					// Format the amount with thousands separator
-					amountStr := formatCurrency(expense.Amount)
-
-					log.Printf("AmountStr: %s", amountStr)
-
-					// Send response to user
-					responseText := fmt.Sprintf("✅ Disimpan:\nKategori: %s\nJumlah: Rp%s\nDeskripsi: %s",
-						expense.Category,
-						amountStr,
-						expense.Description)
-
-					log.Printf("Response text: %s", responseText)
-
-					msg := tgbotapi.NewMessage(chatID, responseText)
-					sendResult, err := bot.Send(msg)
-					if err != nil {
-						log.Printf("Error sending message to user: %v", err)
-					} else {
-						log.Printf("Message sent successfully: %+v", sendResult)
-					}
-				}()
-			}
+		// Don't keep Telegram waiting when all workers are busy, it will retry
+		ctx, cancel := context.WithTimeout(context.Background(), webhookQueueTimeout)
+		defer cancel()
+
+		if err := h.ReceiveUpdate(ctx, update); err != nil {
+			return c.Status(503).SendString("Service Unavailable")
 		}
 
 		return c.SendString("OK")
 	})
 
-	// Setup webhook route
-	app.Get("/setup-webhook", func(c *fiber.Ctx) error {
-		webhookURL := fmt.Sprintf("%s/webhook", c.BaseURL())
+	// Setup webhook route, protected by ADMIN_TOKEN since it repoints the bot
+	app.Post("/setup-webhook", h.requireAdminToken, func(c *fiber.Ctx) error {
+		baseURL := h.service.PublicURL()
+		if baseURL == "" {
+			baseURL = c.BaseURL()
+		}
+		webhookURL := fmt.Sprintf("%s/webhook", baseURL)
+
+		params := url.Values{}
+		params.Set("url", webhookURL)
+		params.Set("allowed_updates", `["message","callback_query"]`)
+		if h.webhookSecret != "" {
+			params.Set("secret_token", h.webhookSecret)
+		}
 
-		_, err := bot.SetWebhook(tgbotapi.NewWebhook(webhookURL))
+		_, err := h.bot.MakeRequest("setWebhook", params)
 		if err != nil {
-			return c.Status(500).JSON(fiber.Map{
+			return c.Status(502).JSON(fiber.Map{
 				"error": fmt.Sprintf("Failed to set webhook: %v", err),
 			})
 		}
 
 		return c.JSON(fiber.Map{
-			"message": "Webhook set successfully",
-			"url":     webhookURL,
+			"message":  "Webhook set successfully",
+			"url":      webhookURL,
+			"verified": h.webhookSecret != "",
+		})
+	})
+
+	app.Get("/setup-webhook", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderAllow, fiber.MethodPost)
+		return c.Status(405).JSON(fiber.Map{
+			"error": "Use POST with the admin token",
 		})
 	})
 }
 
-func handleCommand(bot *tgbotapi.BotAPI, message *tgbotapi.Message, command string) {
-	chatID := message.Chat.ID
+// ReceiveUpdate records a Telegram update and queues it for processing. It is shared by
+// the webhook endpoint and polling mode, and fails when the update could not be recorded
+// or queued, in which case it should be delivered again.
+func (h *Handlers) ReceiveUpdate(ctx context.Context, update tgbotapi.Update) error {
+	// Telegram retries updates it considers undelivered; handle each update_id only once
+	firstDelivery, err := h.service.MarkUpdateProcessed(update.UpdateID)
+	if err != nil {
+		log.Printf("Failed to record update %d: %v", update.UpdateID, err)
+		return err
+	} else if !firstDelivery {
+		log.Printf("Ignoring duplicate update %d", update.UpdateID)
+		return nil
+	}
 
-	switch command {
-	case "start":
-		helpText := "🤖 Selamat datang di SmartExpenseAI!\n\n" +
-			"Fitur yang tersedia:\n" +
-			"• Kirim pesan biasa untuk mencatat pengeluaran\n" +
-			"• /lihat - Lihat 10 pengeluaran terakhir\n" +
-			"• /bulan - Lihat rekap pengeluaran 30 hari terakhir\n" +
-			"• /hapus - Hapus pengeluaran (contoh: /hapus 5)\n" +
-			"• /update - Update pengeluaran (contoh: /update 5 beli buku 50000 Pendidikan)\n" +
-			"• /bantuan - Tampilkan bantuan ini"
-		msg := tgbotapi.NewMessage(chatID, helpText)
-		bot.Send(msg)
-
-	case "bantuan":
-		helpText := "🤖 Bantuan SmartExpenseAI:\n\n" +
-			"Cara mencatat pengeluaran:\n" +
-			"• Kirim pesan seperti: \"makan nasi padang 25000\" atau \"beli buku 50k\"\n\n" +
-			"Perintah yang tersedia:\n" +
-			"• /lihat - Lihat 10 pengeluaran terakhir kamu\n" +
-			"• /bulan - Lihat rekap pengeluaran 30 hari terakhir per bulan\n" +
-			"• /hapus ID - Hapus pengeluaran, ganti ID dengan nomor pengeluaran\n" +
-			"• /update ID deskripsi jumlah kategori - Update pengeluaran\n" +
-			"• /bantuan - Tampilkan pesan bantuan ini"
-		msg := tgbotapi.NewMessage(chatID, helpText)
-		bot.Send(msg)
-
-	case "lihat":
-		services.ListExpenses(bot, chatID)
-
-	case "bulan":
-		services.GenerateMonthlyRecap(bot, chatID)
+	if err := h.dispatcher.Submit(ctx, update); err != nil {
+		log.Printf("Failed to queue update %d: %v", update.UpdateID, err)
 
-	case "hapus":
-		// Extract expense ID from command arguments
-		args := message.CommandArguments()
-		if args == "" {
-			msg := tgbotapi.NewMessage(chatID, "Silakan berikan ID pengeluaran yang ingin dihapus.\nContoh: /hapus 5")
-			bot.Send(msg)
-			return
+		// Forget the update so the redelivery is processed
+		if err := h.service.UnmarkUpdateProcessed(update.UpdateID); err != nil {
+			log.Printf("Failed to forget update %d: %v", update.UpdateID, err)
 		}
+		return err
+	}
 
-		// Parse the expense ID
-		expenseID, err := strconv.ParseUint(args, 10, 32)
-		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, "ID pengeluaran harus berupa angka.\nContoh: /hapus 5")
-			bot.Send(msg)
+	return nil
+}
+
+// ProcessUpdate dispatches a Telegram update to the matching handler; it runs on a dispatcher worker
+func (h *Handlers) ProcessUpdate(ctx context.Context, update tgbotapi.Update) {
+	// Process inline button presses
+	if update.CallbackQuery != nil {
+		if update.CallbackQuery.From == nil || int64(update.CallbackQuery.From.ID) != h.allowedUserID {
+			h.bot.AnswerCallbackQuery(tgbotapi.NewCallback(update.CallbackQuery.ID, h.notAuthorized(update.CallbackQuery.From)))
 			return
 		}
+		if update.CallbackQuery.Message != nil {
+			h.detectLanguage(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.From)
+		}
 
-		services.DeleteExpense(bot, chatID, uint(expenseID))
+		h.handleCallback(update.CallbackQuery)
+		return
+	}
 
-	case "update":
-		// Extract arguments from command
-		args := message.CommandArguments()
-		if args == "" {
-			msg := tgbotapi.NewMessage(chatID, "Format salah. Gunakan: /update ID deskripsi jumlah kategori\nContoh: /update 5 beli buku 50000 Pendidikan")
-			bot.Send(msg)
+	// Process documents as bank statement imports
+	if update.Message != nil && update.Message.Document != nil {
+		if int64(update.Message.From.ID) != h.allowedUserID {
+			h.view.Text(update.Message.Chat.ID, h.notAuthorized(update.Message.From))
 			return
 		}
+		h.detectLanguage(update.Message.Chat.ID, update.Message.From)
 
-		// Parse the arguments (ID, description, amount, category)
-		parts := strings.SplitN(args, " ", 4)
-		if len(parts) < 4 {
-			msg := tgbotapi.NewMessage(chatID, "Format salah. Gunakan: /update ID deskripsi jumlah kategori\nContoh: /update 5 beli buku 50000 Pendidikan")
-			bot.Send(msg)
-			return
-		}
+		h.handleDocument(ctx, update.Message)
+		return
+	}
 
-		// Parse expense ID
-		expenseID, err := strconv.ParseUint(parts[0], 10, 32)
-		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, "ID pengeluaran harus berupa angka.\nContoh: /update 5 beli buku 50000 Pendidikan")
-			bot.Send(msg)
+	// Process text messages only
+	if update.Message != nil && update.Message.Text != "" {
+		// Check if user is authorized
+		if int64(update.Message.From.ID) != h.allowedUserID {
+			h.view.Text(update.Message.Chat.ID, h.notAuthorized(update.Message.From))
 			return
 		}
+		h.detectLanguage(update.Message.Chat.ID, update.Message.From)
+
+		// Check if it's a command
+		if update.Message.IsCommand() {
+			command := update.Message.Command()
+			h.handleCommand(ctx, update.Message, command)
+		} else {
+			// Plain text is an expense unless it reads like one of the commands
+			h.handleText(ctx, update.Message)
+		}
+	}
+}
 
-		description := parts[1]
-		amountStr := parts[2]
-		category := parts[3]
+// handleExpenseText parses a natural language message as an expense and saves it
+func (h *Handlers) handleExpenseText(ctx context.Context, message *tgbotapi.Message) {
+	text := message.Text
+	chatID := message.Chat.ID
 
-		// Parse amount
-		amount, err := strconv.ParseFloat(amountStr, 64)
-		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, "Jumlah harus berupa angka.\nContoh: /update 5 beli buku 50000 Pendidikan")
-			bot.Send(msg)
-			return
+	log.Printf("Received text: %s", text)
+
+	// Parse the expense using AI (only for expense extraction)
+	expense, err := h.service.ParseExpense(ctx, text)
+	if err != nil {
+		log.Printf("Error parsing expense: %v", err)
+
+		// Instead of generic error message, provide helpful guidance
+		h.view.Message(chatID, "expense.parse_failed")
+		return
+	}
+
+	// Only save if there's actual expense data (amount > 0)
+	if expense.Amount <= 0 {
+		// No expense data found, provide helpful response
+		h.view.Message(chatID, "expense.not_recognized")
+		return
+	}
+
+	log.Printf("Parsed expense: %+v", expense)
+
+	// Set the user ID for the expense
+	expense.UserID = uint(h.allowedUserID)
+
+	// Save to database, asking first if it looks like a duplicate
+	result, err := h.service.SaveExpense(expense)
+	if err != nil {
+		log.Printf("Error saving expense to database: %v", err)
+		h.view.Message(chatID, "expense.save_failed")
+		return
+	}
+
+	if result.Duplicate != nil {
+		h.view.DuplicateQuestion(chatID, result.Duplicate, result.Token)
+		return
+	}
+
+	log.Printf("Saved expense to database")
+	h.view.ExpenseSaved(chatID, result.Expense)
+}
+
+// handleCallback dispatches inline button presses by their callback data
+func (h *Handlers) handleCallback(query *tgbotapi.CallbackQuery) {
+	// Acknowledge the press so Telegram stops the loading indicator
+	h.bot.AnswerCallbackQuery(tgbotapi.NewCallback(query.ID, ""))
+
+	if query.Message == nil {
+		return
+	}
+	chatID := query.Message.Chat.ID
+	messageID := query.Message.MessageID
+
+	switch {
+	case query.Data == presenter.ImportConfirmCallback:
+		h.confirmImport(chatID, messageID)
+	case query.Data == presenter.ImportCancelCallback:
+		if err := h.service.CancelImport(uint(chatID)); err != nil {
+			log.Printf("Error cancelling import: %v", err)
 		}
+		h.view.EditMessage(chatID, messageID, "import.cancelled")
+	case strings.HasPrefix(query.Data, presenter.DuplicateKeepCallback):
+		h.resolveDuplicate(chatID, messageID, strings.TrimPrefix(query.Data, presenter.DuplicateKeepCallback), true)
+	case strings.HasPrefix(query.Data, presenter.DuplicateDiscardCallback):
+		h.resolveDuplicate(chatID, messageID, strings.TrimPrefix(query.Data, presenter.DuplicateDiscardCallback), false)
+	case strings.HasPrefix(query.Data, presenter.ListOlderCallback):
+		h.browseExpenses(chatID, messageID, strings.TrimPrefix(query.Data, presenter.ListOlderCallback), false)
+	case strings.HasPrefix(query.Data, presenter.ListNewerCallback):
+		h.browseExpenses(chatID, messageID, strings.TrimPrefix(query.Data, presenter.ListNewerCallback), true)
+	case strings.HasPrefix(query.Data, presenter.ExpenseEditCallback):
+		h.withExpense(chatID, strings.TrimPrefix(query.Data, presenter.ExpenseEditCallback), func(expense *models.Expense) {
+			h.startEdit(chatID, expense.ID)
+		})
+	case strings.HasPrefix(query.Data, presenter.EditFieldCallback):
+		h.selectEditField(chatID, messageID, strings.TrimPrefix(query.Data, presenter.EditFieldCallback))
+	case query.Data == presenter.EditBackCallback:
+		h.selectEditField(chatID, messageID, "")
+	case query.Data == presenter.EditDoneCallback:
+		h.finishEdit(chatID, messageID)
+	case strings.HasPrefix(query.Data, presenter.ExpenseDeleteCallback):
+		h.withExpense(chatID, strings.TrimPrefix(query.Data, presenter.ExpenseDeleteCallback), func(expense *models.Expense) {
+			h.view.DeleteQuestion(chatID, messageID, expense)
+		})
+	case strings.HasPrefix(query.Data, presenter.ExpenseDeleteConfirmCallback):
+		h.deleteListedExpense(chatID, messageID, strings.TrimPrefix(query.Data, presenter.ExpenseDeleteConfirmCallback))
+	default:
+		log.Printf("Unknown callback data: %s", query.Data)
+	}
+}
+
+// resolveDuplicate handles the answer to a duplicate question: keep saves the pending expense, otherwise it is discarded
+func (h *Handlers) resolveDuplicate(chatID int64, messageID int, token string, keep bool) {
+	expense, err := h.service.ResolveDuplicate(uint(chatID), token, keep)
+	if errors.Is(err, services.ErrExpired) {
+		h.view.EditMessage(chatID, messageID, "duplicate.expired")
+		return
+	}
+	if err != nil {
+		log.Printf("Error saving expense to database: %v", err)
+		h.view.Message(chatID, "expense.save_failed")
+		return
+	}
+
+	if expense == nil {
+		h.view.EditMessage(chatID, messageID, "duplicate.discarded")
+		return
+	}
+
+	h.view.EditMessage(chatID, messageID, "duplicate.kept")
+	h.view.ExpenseSaved(chatID, expense)
+}
+
+// confirmImport saves the previewed import of the chat and updates the preview message
+func (h *Handlers) confirmImport(chatID int64, messageID int) {
+	result, err := h.service.ConfirmImport(uint(chatID))
+	if errors.Is(err, services.ErrExpired) {
+		h.view.EditMessage(chatID, messageID, "import.expired")
+		return
+	}
+	if err != nil {
+		log.Printf("Error saving imported expenses: %v", err)
+		h.view.EditMessage(chatID, messageID, "import.save_failed")
+		return
+	}
+
+	h.view.ImportDone(chatID, messageID, result)
+}
+
+// handleDocument downloads a statement file and shows its import preview
+func (h *Handlers) handleDocument(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	document := message.Document
+
+	if !strings.HasSuffix(strings.ToLower(document.FileName), ".csv") {
+		h.view.Message(chatID, "import.not_csv")
+		return
+	}
+
+	maxSize := h.service.MaxImportFileSize()
+	if int64(document.FileSize) > maxSize {
+		h.view.Message(chatID, "import.too_large")
+		return
+	}
+
+	data, err := h.downloadFile(ctx, document.FileID, maxSize)
+	if err != nil {
+		log.Printf("Error downloading import file: %v", err)
+		h.view.Message(chatID, "import.download_failed")
+		return
+	}
+
+	preview, err := h.service.PreviewImport(ctx, uint(chatID), data)
+	if errors.Is(err, services.ErrUnreadableStatement) {
+		h.view.ImportUnreadable(chatID)
+		return
+	}
+	if err != nil {
+		log.Printf("Error checking import duplicates: %v", err)
+		h.view.Message(chatID, "import.duplicate_check_failed")
+		return
+	}
+
+	h.view.ImportPreview(chatID, preview)
+}
+
+// downloadFile fetches a file sent to the bot, refusing files above maxSize
+func (h *Handlers) downloadFile(ctx context.Context, fileID string, maxSize int64) ([]byte, error) {
+	fileURL, err := h.bot.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("file download failed with status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxSize)
+	}
+	return data, nil
+}
+
+// handleCommand runs the registered command, or one of its aliases
+func (h *Handlers) handleCommand(ctx context.Context, message *tgbotapi.Message, command string) {
+	cmd, ok := h.commands.lookup(command)
+	if !ok {
+		h.view.Message(message.Chat.ID, "command.unknown")
+		return
+	}
+	cmd.Run(ctx, message)
+}
+
+// deleteCommand deletes the expense with the ID given after /hapus
+func (h *Handlers) deleteCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	// Extract expense ID from command arguments
+	args := message.CommandArguments()
+	if args == "" {
+		h.view.Message(chatID, "delete.missing_id")
+		return
+	}
+
+	// Parse the expense ID
+	expenseID, err := strconv.ParseUint(args, 10, 32)
+	if err != nil {
+		h.view.Message(chatID, "delete.invalid_id")
+		return
+	}
+
+	h.deleteExpense(chatID, uint(expenseID))
+}
+
+// updateCommand starts the guided edit of the expense given after /update
+func (h *Handlers) updateCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	expenseID, err := strconv.ParseUint(strings.TrimSpace(message.CommandArguments()), 10, 32)
+	if err != nil {
+		h.view.Message(chatID, "update.missing_id")
+		return
+	}
+	h.startEdit(chatID, uint(expenseID))
+}
+
+// historyCommand sends the audit log of the expense given after /riwayat
+func (h *Handlers) historyCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	expenseID, err := strconv.ParseUint(strings.TrimSpace(message.CommandArguments()), 10, 32)
+	if err != nil {
+		h.view.Message(chatID, "history.missing_id")
+		return
+	}
+
+	h.sendExpenseHistory(chatID, uint(expenseID))
+}
+
+// restoreCommand brings back the deleted expense given after /pulihkan
+func (h *Handlers) restoreCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	expenseID, err := strconv.ParseUint(strings.TrimSpace(message.CommandArguments()), 10, 32)
+	if err != nil {
+		h.view.Message(chatID, "restore.missing_id")
+		return
+	}
+
+	h.restoreExpense(chatID, uint(expenseID))
+}
+
+// askCommand answers the question given after /tanya
+func (h *Handlers) askCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	question := strings.TrimSpace(message.CommandArguments())
+	if question == "" {
+		h.view.Message(chatID, "ask.missing_question")
+		return
+	}
+
+	h.answerQuestion(ctx, chatID, question)
+}
+
+// searchCommand searches expenses with the filters given after /cari
+func (h *Handlers) searchCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	filter, page, args, err := parseSearchArgs(message.CommandArguments(), h.location)
+	if err != nil {
+		h.view.Message(chatID, "search.invalid")
+		return
+	}
 
-		services.UpdateExpense(bot, chatID, uint(expenseID), description, amount, category)
+	h.searchExpenses(chatID, filter, page, args)
+}
+
+// dailyCommand sends today's digest, or schedules or turns off the daily one
+func (h *Handlers) dailyCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
 
+	args := strings.TrimSpace(message.CommandArguments())
+	switch strings.ToLower(args) {
+	case "":
+		// Without arguments, send today's digest right away
+		h.sendDailyDigest(chatID, time.Now())
+	case "off", "mati":
+		h.setDailyDigest(chatID, false, "")
 	default:
-		msg := tgbotapi.NewMessage(chatID, "Perintah tidak dikenali. Gunakan /bantuan untuk melihat bantuan.")
-		bot.Send(msg)
+		h.setDailyDigest(chatID, true, args)
 	}
 }
 
-// handleNaturalCommand handles commands detected from natural language
-func handleNaturalCommand(bot *tgbotapi.BotAPI, chatID int64, command string, argsStr string, originalText string) {
-	switch command {
-	case "list":
-		services.ListExpenses(bot, chatID)
-	case "monthly":
-		services.GenerateMonthlyRecap(bot, chatID)
-	case "delete":
-		// Extract ID from args
-		// Simplified: assume argsStr contains the ID
-		argsStr = strings.Trim(argsStr, "[]\"")
-		if argsStr != "" && argsStr != "[]" && argsStr != "" {
-			// Remove quotes if present
-			argsStr = strings.ReplaceAll(argsStr, "\"", "")
-			parts := strings.Split(argsStr, ",")
-			if len(parts) > 0 {
-				idStr := strings.TrimSpace(parts[0])
-				if idStr != "" {
-					id, err := strconv.ParseUint(idStr, 10, 32)
-					if err != nil {
-						msg := tgbotapi.NewMessage(chatID, "ID pengeluaran tidak valid.")
-						bot.Send(msg)
-						return
-					}
-					services.DeleteExpense(bot, chatID, uint(id))
-					return
-				}
-			}
-		}
-		// If no ID provided or invalid
-		msg := tgbotapi.NewMessage(chatID, "Silakan berikan ID pengeluaran yang ingin dihapus.")
-		bot.Send(msg)
-	case "update":
-		// For update, we need to parse the original text to extract ID, description, amount, and category
-		// This is more complex and we'll implement a simplified version
-		parts := strings.Split(originalText, " ")
-		if len(parts) >= 4 {
-			// Look for a number in the text (the ID)
-			idStr := ""
-			for _, part := range parts {
-				if _, err := strconv.Atoi(part); err == nil {
-					idStr = part
-					break
-				}
-			}
-
-			if idStr != "" {
-				id, err := strconv.ParseUint(idStr, 10, 32)
-				if err != nil {
-					msg := tgbotapi.NewMessage(chatID, "ID pengeluaran tidak valid.")
-					bot.Send(msg)
-					return
-				}
-
-				// For now, we'll just send a message to indicate update functionality
-				// In a real implementation, we'd parse the description, amount, and category
-				msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Fitur pengubahan pengeluaran ID %d sedang dalam pengembangan. Silakan gunakan perintah /update untuk saat ini.", id))
-				bot.Send(msg)
-				return
-			}
-		}
+// budgetCommand sets the daily budget given after /anggaran
+func (h *Handlers) budgetCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		h.view.Message(chatID, "budget.missing")
+		return
+	}
+
+	amount, err := strconv.ParseFloat(args, 64)
+	if err != nil || amount < 0 {
+		h.view.Message(chatID, "budget.invalid")
+		return
+	}
+
+	h.setDailyBudget(chatID, amount)
+}
+
+// reminderCommand sets after how many days without expenses the user is reminded
+func (h *Handlers) reminderCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
 
-		msg := tgbotapi.NewMessage(chatID, "Format tidak dikenali. Untuk mengupdate pengeluaran, sebutkan ID pengeluaran yang ingin diubah.")
-		bot.Send(msg)
-	case "help":
-		helpText := "🤖 Bantuan SmartExpenseAI:\n\n" +
-			"Cara mencatat pengeluaran:\n" +
-			"• Kirim pesan seperti: \"makan nasi padang 25000\" atau \"beli buku 50k\"\n\n" +
-			"Perintah alami yang bisa kamu gunakan:\n" +
-			"• \"lihat pengeluaranku\" - Lihat 10 pengeluaran terakhir kamu\n" +
-			"• \"rekap bulan ini\" - Lihat rekap pengeluaran 30 hari terakhir\n" +
-			"• \"hapus pengeluaran 5\" - Hapus pengeluaran dengan ID tertentu\n" +
-			"• \"bantuan\" - Tampilkan pesan bantuan ini"
-		msg := tgbotapi.NewMessage(chatID, helpText)
-		bot.Send(msg)
-	case "weekly":
-		// Call the weekly recap function for the user
-		services.CallWeeklyRecapForUser(bot, chatID)
+	args := strings.TrimSpace(message.CommandArguments())
+	if strings.EqualFold(args, "off") || strings.EqualFold(args, "mati") {
+		h.setInactivityReminder(chatID, 0)
+		return
+	}
+
+	days, err := strconv.Atoi(args)
+	if err != nil || days < 0 {
+		h.view.Message(chatID, "reminder.invalid")
+		return
+	}
+
+	h.setInactivityReminder(chatID, days)
+}
+
+// importCommand explains how to import a bank statement
+func (h *Handlers) importCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	h.view.Message(chatID, "import.help", strings.Join(importer.Formats(), ", "))
+}
+
+// tokenCommand creates, lists or revokes REST API tokens
+func (h *Handlers) tokenCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+
+	switch strings.ToLower(strings.TrimSpace(message.CommandArguments())) {
+	case "":
+		h.generateAPIToken(chatID)
+	case "daftar":
+		h.listAPITokens(chatID)
+	case "hapus":
+		h.revokeAPITokens(chatID)
 	default:
-		// For unknown commands, send a message
-		msg := tgbotapi.NewMessage(chatID, "Perintah tidak dikenali. Gunakan perintah seperti 'lihat pengeluaranku' atau kirim pesan untuk mencatat pengeluaran baru.")
-		bot.Send(msg)
+		h.view.Message(chatID, "token.usage")
 	}
 }
 
-// isLikelyCommand checks if text likely contains a command
-func isLikelyCommand(text string) bool {
-	lowerText := strings.ToLower(text)
+// exportCommand sends the expenses as files, filtered by the arguments after /ekspor
+func (h *Handlers) exportCommand(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
 
-	// Check for keywords that indicate commands
-	commandKeywords := []string{
-		"lihat", "tampilkan", "pengeluaranku", "daftar", "list",
-		"rekap", "ringkasan", "summary", "bulan", "minggu",
-		"hapus", "delete", "remove", "bantuan", "help",
-		"apa yang", "perintah", "commands", "cara", "histori", "semua",
-		"terakhir", "pengeluaran", "my expenses", "show expenses",
+	formats, filter, err := parseExportArgs(message.CommandArguments(), h.location)
+	if err != nil {
+		h.view.Message(chatID, "export.invalid")
+		return
 	}
 
-	for _, keyword := range commandKeywords {
-		if strings.Contains(lowerText, keyword) {
-			return true
-		}
+	h.exportExpenses(chatID, formats, filter)
+}
+
+// listExpenses sends the latest page of expenses to the user
+func (h *Handlers) listExpenses(chatID int64) {
+	page, err := h.service.BrowseExpenses(uint(chatID), nil, false)
+	if err != nil {
+		log.Printf("Error fetching expenses: %v", err)
+		return
 	}
-	return false
+	h.view.ExpenseList(chatID, page)
 }
 
-// detectLikelyCommand detects what type of command this likely is
-func detectLikelyCommand(text string) (string, []string) {
-	lowerText := strings.ToLower(text)
+// browseExpenses shows another page in place of a /lihat message
+func (h *Handlers) browseExpenses(chatID int64, messageID int, cursorData string, newer bool) {
+	cursor, err := presenter.ParseListCursor(cursorData)
+	if err != nil {
+		log.Printf("Error reading list cursor: %v", err)
+		return
+	}
 
-	// Check for list commands
-	if containsAny(lowerText, []string{
-		"lihat pengeluaran", "tampilkan pengeluaran", "pengeluaranku", "daftar pengeluaran",
-		"list pengeluaran", "show expenses", "my expenses", "lihat rekap", "lihat daftar",
-		"tampilkan daftar", "daftar terakhir", "lihat terakhir", "pengeluaran terakhir",
-		"lihat semua", "tampilkan semua", "lihat histori", "tampilkan histori"}) {
-		return "list", []string{}
+	page, err := h.service.BrowseExpenses(uint(chatID), cursor, newer)
+	if err != nil {
+		log.Printf("Error fetching expenses: %v", err)
+		return
 	}
+	h.view.EditExpenseList(chatID, messageID, page)
+}
 
-	// Check for monthly recap commands
-	if containsAny(lowerText, []string{"rekap bulan", "pengeluaran bulan", "ringkasan bulan", "summary bulan", "monthly recap", "month summary"}) {
-		return "monthly", []string{}
+// withExpense runs fn with the expense whose ID a button or a plain-text command carries
+func (h *Handlers) withExpense(chatID int64, id string, fn func(expense *models.Expense)) {
+	expenseID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		log.Printf("Invalid expense ID in callback data: %s", id)
+		return
 	}
 
-	// Check for delete commands (with ID)
-	if containsAny(lowerText, []string{"hapus", "delete"}) {
-		// Extract ID from text
-		re := regexp.MustCompile(`\d+`)
-		matches := re.FindAllString(text, -1)
-		if len(matches) > 0 {
-			return "delete", []string{matches[0]}
-		}
-		return "delete", []string{}
+	expense, err := h.service.GetExpense(uint(chatID), uint(expenseID))
+	if errors.Is(err, repository.ErrNotFound) {
+		h.view.Message(chatID, "expense.not_found", expenseID)
+		return
 	}
+	if err != nil {
+		log.Printf("Error fetching expense: %v", err)
+		return
+	}
+	fn(expense)
+}
 
-	// Check for help commands
-	if containsAny(lowerText, []string{"bantuan", "help", "cara pakai", "perintah", "commands", "tutor", "cara guna"}) {
-		return "help", []string{}
+// deleteListedExpense deletes an expense confirmed from /lihat
+func (h *Handlers) deleteListedExpense(chatID int64, messageID int, id string) {
+	expenseID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		log.Printf("Invalid expense ID in callback data: %s", id)
+		return
 	}
 
-	// Check for weekly recap commands
-	if containsAny(lowerText, []string{"rekap minggu", "pengeluaran minggu", "ringkasan minggu", "summary minggu"}) {
-		return "weekly", []string{}
+	err = h.service.DeleteExpense(uint(chatID), uint(expenseID))
+	if errors.Is(err, repository.ErrNotFound) {
+		h.view.EditMessage(chatID, messageID, "expense.not_found", expenseID)
+		return
 	}
+	if err != nil {
+		log.Printf("Error deleting expense: %v", err)
+		h.view.Message(chatID, "delete.failed", expenseID)
+		return
+	}
+	h.view.ExpenseDeletedFromList(chatID, messageID, uint(expenseID))
+}
 
-	return "", []string{}
+// searchExpenses sends one page of the user's expenses matching a /cari search
+func (h *Handlers) searchExpenses(chatID int64, filter repository.ExpenseFilter, page int, args string) {
+	result, err := h.service.SearchExpenses(uint(chatID), filter, page)
+	if err != nil {
+		log.Printf("Error searching expenses: %v", err)
+		h.view.Message(chatID, "search.failed")
+		return
+	}
+	h.view.SearchResults(chatID, result, args)
 }
 
-// containsAny checks if the text contains any of the keywords
-func containsAny(text string, keywords []string) bool {
-	for _, keyword := range keywords {
-		if strings.Contains(text, keyword) {
-			return true
-		}
+func (h *Handlers) sendMonthlyRecap(chatID int64) {
+	recap, err := h.service.MonthlyRecap(uint(chatID))
+	if err != nil {
+		log.Printf("Error fetching expenses: %v", err)
+		return
+	}
+	h.view.MonthlyRecap(chatID, recap)
+}
+
+func (h *Handlers) deleteExpense(chatID int64, expenseID uint) {
+	err := h.service.DeleteExpense(uint(chatID), expenseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.view.Message(chatID, "expense.not_found", expenseID)
+		return
 	}
-	return false
+	if err != nil {
+		log.Printf("Error deleting expense: %v", err)
+		h.view.Message(chatID, "delete.failed", expenseID)
+		return
+	}
+	h.view.ExpenseDeleted(chatID, expenseID)
+}
+
+// undo reverts the user's last change
+func (h *Handlers) undo(chatID int64) {
+	result, err := h.service.Undo(uint(chatID))
+	if errors.Is(err, services.ErrNothingToUndo) {
+		h.view.Message(chatID, "undo.nothing")
+		return
+	}
+	if err != nil {
+		log.Printf("Error undoing last change: %v", err)
+		h.view.Message(chatID, "undo.failed")
+		return
+	}
+	h.view.Undone(chatID, result)
+}
+
+func (h *Handlers) sendExpenseHistory(chatID int64, expenseID uint) {
+	events, err := h.service.ExpenseHistory(uint(chatID), expenseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.view.Message(chatID, "expense.not_found", expenseID)
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching expense history: %v", err)
+		h.view.Message(chatID, "history.failed")
+		return
+	}
+	h.view.ExpenseHistory(chatID, expenseID, events)
+}
+
+func (h *Handlers) restoreExpense(chatID int64, expenseID uint) {
+	expense, err := h.service.RestoreExpense(uint(chatID), expenseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.view.Message(chatID, "restore.not_found", expenseID)
+		return
+	}
+	if err != nil {
+		log.Printf("Error restoring expense: %v", err)
+		h.view.Message(chatID, "restore.failed", expenseID)
+		return
+	}
+	h.view.ExpenseRestored(chatID, expense)
+}
+
+func (h *Handlers) setDailyDigest(chatID int64, enabled bool, digestTime string) {
+	pref, err := h.service.SetDailyDigest(uint(chatID), enabled, digestTime)
+	if errors.Is(err, services.ErrInvalidTime) {
+		h.view.Message(chatID, "digest.invalid_time")
+		return
+	}
+	if err != nil {
+		log.Printf("Error saving preferences: %v", err)
+		h.view.Message(chatID, "digest.save_failed")
+		return
+	}
+
+	h.scheduleDigest(pref)
+	h.view.DailyDigestSet(chatID, pref)
+}
+
+func (h *Handlers) setDailyBudget(chatID int64, amount float64) {
+	if _, err := h.service.SetDailyBudget(uint(chatID), amount); err != nil {
+		log.Printf("Error saving preferences: %v", err)
+		h.view.Message(chatID, "budget.save_failed")
+		return
+	}
+	h.view.DailyBudgetSet(chatID, amount)
+}
+
+func (h *Handlers) setInactivityReminder(chatID int64, days int) {
+	if err := h.service.SetInactivityReminder(uint(chatID), days); err != nil {
+		log.Printf("Error saving preferences: %v", err)
+		h.view.Message(chatID, "reminder.save_failed")
+		return
+	}
+	h.view.InactivityReminderSet(chatID, days)
+}
+
+func (h *Handlers) sendJobStatus(chatID int64) {
+	statuses, err := h.scheduler.Statuses()
+	if err != nil {
+		log.Printf("Error fetching job statuses: %v", err)
+		h.view.Message(chatID, "jobs.failed")
+		return
+	}
+	h.view.JobStatuses(chatID, statuses)
+}
+
+func (h *Handlers) generateAPIToken(chatID int64) {
+	raw, err := h.service.GenerateAPIToken(uint(chatID))
+	if err != nil {
+		log.Printf("Error generating API token: %v", err)
+		h.view.Message(chatID, "token.create_failed")
+		return
+	}
+	h.view.APIToken(chatID, raw)
 }
 
-// Helper function to format currency with thousands separator
-func formatCurrency(amount float64) string {
-	// Convert to integer to avoid decimal places
-	amt := int64(amount)
+func (h *Handlers) listAPITokens(chatID int64) {
+	tokens, err := h.service.ListAPITokens(uint(chatID))
+	if err != nil {
+		log.Printf("Error fetching API tokens: %v", err)
+		h.view.Message(chatID, "token.list_failed")
+		return
+	}
+	h.view.APITokens(chatID, tokens)
+}
+
+func (h *Handlers) revokeAPITokens(chatID int64) {
+	count, err := h.service.RevokeAPITokens(uint(chatID))
+	if err != nil {
+		log.Printf("Error revoking API tokens: %v", err)
+		h.view.Message(chatID, "token.revoke_failed")
+		return
+	}
+	h.view.Message(chatID, "token.revoked", count)
+}
+
+func (h *Handlers) sendDashboardLink(chatID int64) {
+	link, err := h.service.CreateLoginLink(uint(chatID))
+	if errors.Is(err, services.ErrNoPublicURL) {
+		h.view.Message(chatID, "dashboard.not_configured")
+		return
+	}
+	if err != nil {
+		log.Printf("Error creating login link: %v", err)
+		h.view.Message(chatID, "dashboard.failed")
+		return
+	}
+	h.view.DashboardLink(chatID, link)
+}
+
+func (h *Handlers) exportExpenses(chatID int64, formats []string, filter repository.ExpenseFilter) {
+	result, err := h.service.ExportExpenses(uint(chatID), formats, filter)
+	if err != nil {
+		log.Printf("Error fetching expenses for export: %v", err)
+		h.view.Message(chatID, "export.fetch_failed")
+		return
+	}
+	h.view.ExportFiles(chatID, result)
+}
+
+// handleText routes a plain-text message to a command or, by default, to expense logging
+func (h *Handlers) handleText(ctx context.Context, message *tgbotapi.Message) {
+	// A dialog waiting for an answer takes the message
+	if h.handleDialogMessage(ctx, message) {
+		return
+	}
+
+	intent := h.service.ClassifyIntent(ctx, message.Text)
+	if intent.Kind == services.IntentExpense {
+		h.handleExpenseText(ctx, message)
+		return
+	}
 
-	// Convert to string
-	s := strconv.FormatInt(amt, 10)
+	log.Printf("Detected %s intent in text: %s", intent.Kind, message.Text)
+	h.handleNaturalCommand(ctx, message.Chat.ID, intent, message.Text)
+}
 
-	// Add thousands separators from right to left
-	for i := len(s) - 3; i > 0; i -= 3 {
-		s = s[:i] + "." + s[i:]
+// handleNaturalCommand handles commands detected from natural language
+func (h *Handlers) handleNaturalCommand(ctx context.Context, chatID int64, intent services.Intent, originalText string) {
+	switch intent.Kind {
+	case services.IntentQuestion:
+		h.answerQuestion(ctx, chatID, originalText)
+	case services.IntentList:
+		h.listExpenses(chatID)
+	case services.IntentMonthly:
+		h.sendMonthlyRecap(chatID)
+	case services.IntentWeekly:
+		h.sendWeeklyRecap(chatID)
+	case services.IntentDelete:
+		if intent.Missing {
+			h.view.Message(chatID, "natural.delete.missing_id")
+			return
+		}
+		// A delete read from plain words is confirmed with a button before anything is deleted
+		h.withExpense(chatID, strconv.FormatUint(uint64(intent.ExpenseID), 10), func(expense *models.Expense) {
+			h.view.ConfirmDelete(chatID, expense)
+		})
+	case services.IntentUpdate:
+		if intent.Missing {
+			h.view.Message(chatID, "natural.update.missing")
+			return
+		}
+		h.startEdit(chatID, intent.ExpenseID)
+	case services.IntentBudget:
+		if intent.Missing {
+			h.view.Message(chatID, "natural.budget.missing")
+			return
+		}
+		h.setDailyBudget(chatID, intent.Amount)
+	case services.IntentHelp:
+		h.view.Message(chatID, "natural.help")
+	default:
+		// For unknown commands, send a message
+		h.view.Message(chatID, "natural.unknown")
 	}
+}
 
-	return s
+// answerQuestion answers a question about the user's expenses with numbers and matching rows
+func (h *Handlers) answerQuestion(ctx context.Context, chatID int64, question string) {
+	answer, err := h.service.AnswerQuestion(ctx, uint(chatID), question)
+	if errors.Is(err, services.ErrQuestionNotUnderstood) {
+		h.view.Message(chatID, "ask.not_understood")
+		return
+	}
+	if err != nil {
+		log.Printf("Error answering question: %v", err)
+		h.view.Message(chatID, "ask.failed")
+		return
+	}
+	h.view.Answer(chatID, answer)
 }
@@ -2,13 +2,15 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+	"strings"
 	"time"
 
+	"SmartExpenseAI/internal/config"
 	"SmartExpenseAI/internal/models"
 )
 
@@ -31,13 +33,48 @@ type Choice struct {
 	Message Message `json:"message"`
 }
 
-func ParseExpense(text string) (models.Expense, error) {
+// AI extracts structured data from free text
+type AI interface {
+	// Configured reports whether the provider can be called at all
+	Configured() bool
+	// ParseExpense extracts the expense in a message, dated relative to now
+	ParseExpense(ctx context.Context, text string, now time.Time) (models.Expense, error)
+	// CategorizeDescriptions returns a category for each description, in the same order
+	CategorizeDescriptions(ctx context.Context, descriptions []string) ([]string, error)
+	// ClassifyIntent tells whether a message logs an expense or asks for a command
+	ClassifyIntent(ctx context.Context, text string) (Intent, error)
+	// PlanQuestion turns a question about the user's expenses into a query plan
+	PlanQuestion(ctx context.Context, question string, now time.Time) (QueryPlan, error)
+}
+
+// OpenRouter is the AI implementation backed by an OpenAI-compatible chat completions API
+type OpenRouter struct {
+	config config.AIConfig
+	client *http.Client
+}
+
+var _ AI = (*OpenRouter)(nil)
+
+func NewOpenRouter(cfg config.AIConfig) *OpenRouter {
+	return &OpenRouter{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Configured reports whether an AI provider API key is set
+func (o *OpenRouter) Configured() bool {
+	return o.config.APIKey != ""
+}
+
+// ParseExpense asks the AI for the expense in a message. Dates are read in the location of now,
+// which is also the day used when the message names none.
+func (o *OpenRouter) ParseExpense(ctx context.Context, text string, now time.Time) (models.Expense, error) {
 	var expense models.Expense
 
-	// Get the API key from environment
-	apiKey := os.Getenv("OPENROUTER_API_KEY")
-	if apiKey == "" {
-		return expense, fmt.Errorf("OPENROUTER_API_KEY environment variable is not set")
+	// The API key comes from the AI settings, which leave it empty when the AI is not used
+	if !o.Configured() {
+		return expense, fmt.Errorf("the AI is not configured: OPENROUTER_API_KEY is not set")
 	}
 
 	// Prepare the prompt for the AI - focus only on expense extraction
@@ -49,6 +86,7 @@ func ParseExpense(text string) (models.Expense, error) {
 	{
 		"description": "the item or service purchased",
 		"category": "the category of expense (e.g., Food, Transport, etc.)",
+		"merchant": "the store, restaurant or company paid, if mentioned, otherwise empty",
 		"amount": "the numeric amount in rupiah (as a number)",
 		"date": "the date in YYYY-MM-DD format (use today's date if not specified)"
 	}
@@ -57,13 +95,195 @@ func ParseExpense(text string) (models.Expense, error) {
 	{
 		"description": "",
 		"category": "",
+		"merchant": "",
 		"amount": 0,
 		"date": "%s"
-	}`, text, time.Now().Format("2006-01-02"))
+	}`, text, now.Format("2006-01-02"))
+
+	responseContent, err := o.complete(ctx, prompt)
+	if err != nil {
+		return expense, err
+	}
+
+	// Parse the JSON response from AI
+	var expenseResp struct {
+		Description string  `json:"description"`
+		Category    string  `json:"category"`
+		Merchant    string  `json:"merchant"`
+		Amount      float64 `json:"amount"`
+		Date        string  `json:"date"`
+	}
+
+	if err := json.Unmarshal([]byte(responseContent), &expenseResp); err != nil {
+		return expense, fmt.Errorf("failed to unmarshal expense data: %w", err)
+	}
+
+	// Convert the date string to time.Time
+	var date time.Time
+	if expenseResp.Date != "" {
+		var err error
+		date, err = time.ParseInLocation("2006-01-02", expenseResp.Date, now.Location())
+		if err != nil {
+			// If date parsing fails, use current date
+			date = now
+		}
+	} else {
+		// If no date provided, use current date
+		date = now
+	}
+
+	// Create and return the Expense model
+	expense = models.Expense{
+		Description: expenseResp.Description,
+		Category:    expenseResp.Category,
+		Merchant:    expenseResp.Merchant,
+		Amount:      expenseResp.Amount,
+		Date:        date,
+		CreatedAt:   time.Now(),
+	}
+
+	return expense, nil
+}
+
+// CategorizeDescriptions asks the AI for a category for each description, in the same order.
+// Descriptions the AI could not categorize are returned as empty strings.
+func (o *OpenRouter) CategorizeDescriptions(ctx context.Context, descriptions []string) ([]string, error) {
+	categories := make([]string, len(descriptions))
+	if len(descriptions) == 0 {
+		return categories, nil
+	}
+
+	if !o.Configured() {
+		return categories, fmt.Errorf("the AI is not configured: OPENROUTER_API_KEY is not set")
+	}
+
+	list, err := json.Marshal(descriptions)
+	if err != nil {
+		return categories, fmt.Errorf("failed to marshal descriptions: %w", err)
+	}
+
+	prompt := fmt.Sprintf(`Categorize each of the following bank transaction descriptions as an expense category in Indonesian (e.g., Makanan, Transport, Belanja, Tagihan, Hiburan, Kesehatan, Pendidikan, Lainnya).
 
+	Descriptions (JSON array): %s
+
+	Respond in JSON format with the following structure, keeping the same order and length as the input:
+	{
+		"categories": ["category for the first description", "..."]
+	}`, string(list))
+
+	responseContent, err := o.complete(ctx, prompt)
+	if err != nil {
+		return categories, err
+	}
+
+	var categoriesResp struct {
+		Categories []string `json:"categories"`
+	}
+	if err := json.Unmarshal([]byte(responseContent), &categoriesResp); err != nil {
+		return categories, fmt.Errorf("failed to unmarshal categories: %w", err)
+	}
+
+	for i := range categories {
+		if i < len(categoriesResp.Categories) {
+			categories[i] = categoriesResp.Categories[i]
+		}
+	}
+	return categories, nil
+}
+
+// ClassifyIntent asks the AI whether a message logs an expense or asks for one of the bot's commands
+func (o *OpenRouter) ClassifyIntent(ctx context.Context, text string) (Intent, error) {
+	if !o.Configured() {
+		return Intent{}, fmt.Errorf("the AI is not configured: OPENROUTER_API_KEY is not set")
+	}
+
+	prompt := fmt.Sprintf(`You route messages sent to an Indonesian expense tracking bot. Decide what the user wants.
+
+	Text: "%s"
+
+	Intents:
+	- "expense": the user reports money they spent, e.g. "makan siang 25000"
+	- "list": show their latest expenses
+	- "monthly": recap of the last 30 days
+	- "weekly": recap of the last 7 days
+	- "delete": delete an expense by its ID
+	- "update": change an expense by its ID
+	- "budget": set the daily budget to an amount
+	- "help": explain how to use the bot
+	- "question": a question about their past expenses, e.g. "berapa total jajan kopi bulan ini?"
+
+	Respond in JSON format with the following structure:
+	{
+		"intent": "one of the intents above",
+		"expense_id": 0,
+		"amount": 0
+	}
+
+	Set "expense_id" for delete and update, and "amount" to the daily budget for budget.`, text)
+
+	responseContent, err := o.complete(ctx, prompt)
+	if err != nil {
+		return Intent{}, err
+	}
+
+	var intentResp struct {
+		Intent    string  `json:"intent"`
+		ExpenseID uint    `json:"expense_id"`
+		Amount    float64 `json:"amount"`
+	}
+	if err := json.Unmarshal([]byte(responseContent), &intentResp); err != nil {
+		return Intent{}, fmt.Errorf("failed to unmarshal intent: %w", err)
+	}
+
+	return Intent{
+		Kind:      intentResp.Intent,
+		ExpenseID: intentResp.ExpenseID,
+		Amount:    intentResp.Amount,
+	}, nil
+}
+
+// PlanQuestion asks the AI to translate a question into a query plan. The plan is only data;
+// it is validated and executed by RunQueryPlan.
+func (o *OpenRouter) PlanQuestion(ctx context.Context, question string, now time.Time) (QueryPlan, error) {
+	var plan QueryPlan
+	if !o.Configured() {
+		return plan, fmt.Errorf("the AI is not configured: OPENROUTER_API_KEY is not set")
+	}
+
+	prompt := fmt.Sprintf(`Translate a question about the user's own expenses into a query plan. Today is %s (%s).
+	Expenses have a description (Indonesian, e.g. "kopi susu", "token listrik PLN"), a category (e.g. Makanan, Transport, Tagihan), an amount in rupiah and a date.
+
+	Question: "%s"
+
+	Respond in JSON format with the following structure:
+	{
+		"from": "first date in YYYY-MM-DD format, or empty for no lower bound",
+		"to": "last date (inclusive) in YYYY-MM-DD format, or empty for no upper bound",
+		"category": "exact category name if the question names one, otherwise empty",
+		"keywords": ["lowercase words to look for in the description, including common synonyms, e.g. listrik, pln"],
+		"min_amount": 0,
+		"max_amount": 0,
+		"aggregate": "sum, count, average, max, min, latest or list",
+		"group_by": "empty, category or month",
+		"limit": 10
+	}
+
+	Use "latest" for questions like "kapan terakhir ...", "sum" for "berapa total ...", "max" for the largest expense.`,
+		now.Format("2006-01-02"), now.Weekday(), question)
+
+	responseContent, err := o.complete(ctx, prompt)
+	if err != nil {
+		return plan, err
+	}
+
+	return parseQueryPlan(responseContent)
+}
+
+// complete sends a single-message chat completion requesting a JSON answer and returns its content
+func (o *OpenRouter) complete(ctx context.Context, prompt string) (string, error) {
 	// Prepare the request body
 	requestBody := OpenRouterRequest{
-		Model: "openai/gpt-3.5-turbo",
+		Model: o.config.Model,
 		Messages: []Message{
 			{
 				Role:    "user",
@@ -78,78 +298,41 @@ func ParseExpense(text string) (models.Expense, error) {
 	// Convert request body to JSON
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
-		return expense, fmt.Errorf("failed to marshal request body: %w", err)
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
 	// Create HTTP request
-	req, err := http.NewRequest("POST", "https://openrouter.ai/api/v1/chat/completions", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(o.config.BaseURL, "/")+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return expense, fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Authorization", "Bearer "+o.config.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
 	// Make the API call
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := o.client.Do(req)
 	if err != nil {
-		return expense, fmt.Errorf("failed to make API request: %w", err)
+		return "", fmt.Errorf("failed to make API request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check if the request was successful
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return expense, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
 	// Decode the response
 	var openRouterResp OpenRouterResponse
 	if err := json.NewDecoder(resp.Body).Decode(&openRouterResp); err != nil {
-		return expense, fmt.Errorf("failed to decode response: %w", err)
+		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if len(openRouterResp.Choices) == 0 {
-		return expense, fmt.Errorf("no choices in AI response")
+		return "", fmt.Errorf("no choices in AI response")
 	}
 
-	// Parse the JSON response from AI
-	var expenseResp struct {
-		Description string  `json:"description"`
-		Category    string  `json:"category"`
-		Amount      float64 `json:"amount"`
-		Date        string  `json:"date"`
-	}
-
-	responseContent := openRouterResp.Choices[0].Message.Content
-	if err := json.Unmarshal([]byte(responseContent), &expenseResp); err != nil {
-		return expense, fmt.Errorf("failed to unmarshal expense data: %w", err)
-	}
-
-	// Convert the date string to time.Time
-	var date time.Time
-	if expenseResp.Date != "" {
-		var err error
-		date, err = time.Parse("2006-01-02", expenseResp.Date)
-		if err != nil {
-			// If date parsing fails, use current date
-			date = time.Now()
-		}
-	} else {
-		// If no date provided, use current date
-		date = time.Now()
-	}
-
-	// Create and return the Expense model
-	expense = models.Expense{
-		Description: expenseResp.Description,
-		Category:    expenseResp.Category,
-		Amount:      expenseResp.Amount,
-		Date:        date,
-		CreatedAt:   time.Now(),
-	}
-
-	return expense, nil
+	return openRouterResp.Choices[0].Message.Content, nil
 }
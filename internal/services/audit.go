@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+// ErrNothingToUndo is returned by Undo when every recorded change has already been undone
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// UndoResult describes the change reverted by Undo
+type UndoResult struct {
+	Action string
+	// Expenses are the affected expenses as they are after the undo
+	Expenses []models.Expense
+}
+
+// recordEvents writes the audit events of one action. It runs in the transaction of the change,
+// so that a change is never saved without the events /undo and /riwayat rely on.
+func recordEvents(audit repository.Audit, userID uint, action string, changes ...expenseChange) error {
+	batch, err := newPendingToken()
+	if err != nil {
+		batch = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	events := make([]models.ExpenseEvent, 0, len(changes))
+	for _, change := range changes {
+		events = append(events, models.ExpenseEvent{
+			UserID:    userID,
+			ExpenseID: change.expenseID,
+			Batch:     batch,
+			Action:    action,
+			Before:    change.before,
+			After:     change.after,
+		})
+	}
+
+	if err := audit.CreateExpenseEvents(events); err != nil {
+		return fmt.Errorf("failed to record %s of expenses: %w", action, err)
+	}
+	return nil
+}
+
+// expenseChange is the state of one expense before and after an action
+type expenseChange struct {
+	expenseID uint
+	before    *models.ExpenseSnapshot
+	after     *models.ExpenseSnapshot
+}
+
+func created(expense *models.Expense) expenseChange {
+	return expenseChange{expenseID: expense.ID, after: models.SnapshotOf(expense)}
+}
+
+// Undo reverts the user's most recent change that has not been undone yet. An import is
+// reverted as a whole: the events of the batch are reverted and marked undone in one
+// transaction, so a failure leaves the batch as it was and /undo can be tried again.
+func (s *Service) Undo(userID uint) (*UndoResult, error) {
+	events, err := s.repo.GetLastExpenseEvents(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, ErrNothingToUndo
+	}
+
+	result := &UndoResult{Action: events[0].Action}
+	err = s.repo.Transaction(func(tx repository.Repository) error {
+		for i := len(events) - 1; i >= 0; i-- {
+			expense, err := revert(tx, userID, events[i])
+			if err != nil {
+				return err
+			}
+			result.Expenses = append(result.Expenses, *expense)
+		}
+		return tx.MarkExpenseEventsUndone(userID, events[0].Batch, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// revert applies the opposite of one event and returns the expense afterwards
+func revert(repo repository.Expenses, userID uint, event models.ExpenseEvent) (*models.Expense, error) {
+	switch event.Action {
+	case models.ExpenseCreated, models.ExpenseRestored:
+		expense, err := repo.GetExpenseByID(userID, event.ExpenseID)
+		if err != nil {
+			return nil, err
+		}
+		if err := repo.DeleteExpense(userID, event.ExpenseID); err != nil {
+			return nil, err
+		}
+		return expense, nil
+
+	case models.ExpenseDeleted:
+		expense, err := repo.GetDeletedExpenseByID(userID, event.ExpenseID)
+		if err != nil {
+			return nil, err
+		}
+		if err := repo.RestoreExpense(userID, event.ExpenseID); err != nil {
+			return nil, err
+		}
+		return expense, nil
+
+	default:
+		expense, err := repo.GetExpenseByID(userID, event.ExpenseID)
+		if err != nil {
+			return nil, err
+		}
+		if event.Before != nil {
+			expense.Description = event.Before.Description
+			expense.Category = event.Before.Category
+			expense.Merchant = event.Before.Merchant
+			expense.Account = event.Before.Account
+			expense.Tags = event.Before.Tags
+			expense.Amount = event.Before.Amount
+			expense.Date = event.Before.Date
+		}
+		if err := repo.UpdateExpense(expense); err != nil {
+			return nil, err
+		}
+		return expense, nil
+	}
+}
+
+// RestoreExpense brings back a deleted expense. It returns repository.ErrNotFound
+// when the user has no such deleted expense.
+func (s *Service) RestoreExpense(userID uint, expenseID uint) (*models.Expense, error) {
+	expense, err := s.repo.GetDeletedExpenseByID(userID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Transaction(func(tx repository.Repository) error {
+		if err := tx.RestoreExpense(userID, expenseID); err != nil {
+			return err
+		}
+		return recordEvents(tx, userID, models.ExpenseRestored, expenseChange{expenseID: expenseID, after: models.SnapshotOf(expense)})
+	})
+	if err != nil {
+		return nil, err
+	}
+	expense.DeletedAt.Valid = false
+	return expense, nil
+}
+
+// ExpenseHistory returns the recorded changes of an expense, oldest first. Expenses logged before
+// the audit log existed have none. It returns repository.ErrNotFound when the expense never existed.
+func (s *Service) ExpenseHistory(userID uint, expenseID uint) ([]models.ExpenseEvent, error) {
+	events, err := s.repo.GetExpenseEvents(userID, expenseID)
+	if err != nil || len(events) > 0 {
+		return events, err
+	}
+
+	if _, err := s.repo.GetExpenseByID(userID, expenseID); errors.Is(err, repository.ErrNotFound) {
+		_, err = s.repo.GetDeletedExpenseByID(userID, expenseID)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,19 @@
+package services
+
+import "SmartExpenseAI/internal/models"
+
+// ChatState returns the conversation state of a chat, or nil when it is not in a conversation.
+// Expired states are returned as well; the caller decides how to end them.
+func (s *Service) ChatState(chatID int64) (*models.ChatState, error) {
+	return s.repo.GetChatState(chatID)
+}
+
+// SaveChatState creates or replaces the conversation state of a chat
+func (s *Service) SaveChatState(state *models.ChatState) error {
+	return s.repo.SaveChatState(state)
+}
+
+// EndChatState forgets the conversation state of a chat
+func (s *Service) EndChatState(chatID int64) error {
+	return s.repo.DeleteChatState(chatID)
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+// savePendingConfirmation keeps data as JSON under key until the user answers or ttl passes.
+// It is stored in the repository, so the answer works after a restart and on any instance.
+func (s *Service) savePendingConfirmation(userID uint, key string, data any, ttl time.Duration) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.repo.SavePendingConfirmation(&models.PendingConfirmation{
+		Key:       key,
+		UserID:    userID,
+		Data:      string(encoded),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+}
+
+// takePendingConfirmation removes the user's confirmation under key and decodes it into data.
+// It returns ErrExpired when there is no such confirmation or it is no longer answerable.
+func takePendingConfirmation(repo repository.Confirmations, userID uint, key string, data any) error {
+	confirmation, err := repo.TakePendingConfirmation(userID, key, time.Now())
+	if err != nil {
+		return err
+	}
+	if confirmation == nil {
+		return ErrExpired
+	}
+	return json.Unmarshal([]byte(confirmation.Data), data)
+}
+
+// CleanupPendingConfirmations removes confirmations nobody answered in time
+func (s *Service) CleanupPendingConfirmations() (int64, error) {
+	return s.repo.DeleteExpiredPendingConfirmations(time.Now())
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+const (
+	// LoginLinkTTL is how long a one-time dashboard link stays valid
+	LoginLinkTTL = 10 * time.Minute
+
+	// WebSessionTTL is how long a dashboard login lasts
+	WebSessionTTL = 7 * 24 * time.Hour
+
+	// telegramLoginMaxAge rejects replayed Telegram Login Widget payloads
+	telegramLoginMaxAge = 24 * time.Hour
+)
+
+// ErrNoPublicURL is returned when no externally reachable URL is configured for the dashboard
+var ErrNoPublicURL = errors.New("public URL is not configured")
+
+// PublicURL returns the externally reachable base URL of the app, without trailing slash
+func (s *Service) PublicURL() string {
+	return strings.TrimSuffix(s.publicURL, "/")
+}
+
+// CreateLoginLink returns a one-time login link to the web dashboard
+func (s *Service) CreateLoginLink(userID uint) (string, error) {
+	baseURL := s.PublicURL()
+	if baseURL == "" {
+		return "", ErrNoPublicURL
+	}
+
+	raw, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	token := models.LoginToken{
+		UserID:    userID,
+		TokenHash: HashAPIToken(raw),
+		ExpiresAt: time.Now().Add(LoginLinkTTL),
+	}
+	if err := s.repo.CreateLoginToken(&token); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/dashboard/login?token=%s", baseURL, raw), nil
+}
+
+// RedeemLoginToken consumes a one-time login token and returns the user it belongs to
+func (s *Service) RedeemLoginToken(raw string) (uint, error) {
+	token, err := s.repo.UseLoginToken(HashAPIToken(raw), time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if token == nil {
+		return 0, fmt.Errorf("login link is invalid, expired or already used")
+	}
+	return token.UserID, nil
+}
+
+// VerifyTelegramLogin checks the signature of a Telegram Login Widget payload and returns the user ID.
+// See https://core.telegram.org/widgets/login#checking-authorization
+func VerifyTelegramLogin(params map[string]string, botToken string) (int64, error) {
+	hash := params["hash"]
+	if hash == "" {
+		return 0, fmt.Errorf("missing hash")
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if key != "hash" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, key+"="+params[key])
+	}
+
+	secret := sha256.Sum256([]byte(botToken))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(hash)) {
+		return 0, fmt.Errorf("invalid signature")
+	}
+
+	authDate, err := strconv.ParseInt(params["auth_date"], 10, 64)
+	if err != nil || time.Since(time.Unix(authDate, 0)) > telegramLoginMaxAge {
+		return 0, fmt.Errorf("login data is too old")
+	}
+
+	userID, err := strconv.ParseInt(params["id"], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user id")
+	}
+	return userID, nil
+}
+
+// StartWebSession creates a dashboard session and returns the raw cookie value
+func (s *Service) StartWebSession(userID uint) (string, error) {
+	raw, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+	csrf, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	session := models.WebSession{
+		UserID:    userID,
+		TokenHash: HashAPIToken(raw),
+		CSRFToken: csrf,
+		ExpiresAt: time.Now().Add(WebSessionTTL),
+	}
+	if err := s.repo.CreateWebSession(&session); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// GetWebSession resolves a session cookie value
+func (s *Service) GetWebSession(raw string) (*models.WebSession, error) {
+	return s.repo.GetWebSession(HashAPIToken(raw), time.Now())
+}
+
+// EndWebSession logs a session out
+func (s *Service) EndWebSession(raw string) error {
+	return s.repo.DeleteWebSession(HashAPIToken(raw))
+}
+
+// MarkUpdateProcessed records a Telegram update_id and reports whether it was seen for the first time
+func (s *Service) MarkUpdateProcessed(updateID int) (bool, error) {
+	return s.repo.MarkUpdateProcessed(updateID)
+}
+
+// UnmarkUpdateProcessed forgets a Telegram update_id so a redelivery of it is processed
+func (s *Service) UnmarkUpdateProcessed(updateID int) error {
+	return s.repo.UnmarkUpdateProcessed(updateID)
+}
+
+// CleanupProcessedUpdates forgets update_ids older than a week, once Telegram no longer retries them
+func (s *Service) CleanupProcessedUpdates() (int64, error) {
+	return s.repo.DeleteProcessedUpdatesBefore(time.Now().AddDate(0, 0, -7))
+}
+
+// CleanupExpiredLogins removes expired dashboard login links and sessions
+func (s *Service) CleanupExpiredLogins() error {
+	return s.repo.DeleteExpiredLogins(time.Now())
+}
+
+func randomHex(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+// ErrInvalidTime is returned for a digest time that is not in HH:MM format
+var ErrInvalidTime = errors.New("time must be in HH:MM format")
+
+// Digest summarizes one day of spending against the daily budget
+type Digest struct {
+	Date        time.Time
+	Categories  []repository.CategoryTotal
+	Count       int64
+	Total       float64
+	DailyBudget float64
+}
+
+// Reminder is owed to a user who has not logged an expense for their configured number of days
+type Reminder struct {
+	UserID uint
+	// LastExpense is nil when the user never logged an expense
+	LastExpense  *models.Expense
+	DaysInactive int
+}
+
+// DailyDigest returns the spending of the day of the given time per category, sorted by category name
+func (s *Service) DailyDigest(userID uint, day time.Time) (*Digest, error) {
+	pref, err := s.repo.GetUserPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The day's boundaries in the service timezone
+	startOfDay := s.startOfDay(day)
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+
+	categories, err := s.repo.GetCategoryTotals(userID, repository.ExpenseFilter{From: &startOfDay, To: &endOfDay})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Category < categories[j].Category })
+
+	digest := &Digest{Date: day.In(s.location), Categories: categories, DailyBudget: pref.DailyBudget}
+	for _, category := range categories {
+		digest.Count += category.Count
+		digest.Total += category.Total
+	}
+	return digest, nil
+}
+
+// DueReminders returns the inactivity reminders to send now, at most one per reminder window
+func (s *Service) DueReminders() ([]Reminder, error) {
+	prefs, err := s.repo.GetReminderPreferences()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var reminders []Reminder
+	for _, pref := range prefs {
+		window := time.Duration(pref.ReminderDays) * 24 * time.Hour
+
+		// Don't remind more than once per window
+		if pref.LastReminderAt != nil && now.Sub(*pref.LastReminderAt) < window {
+			continue
+		}
+
+		lastExpense, err := s.repo.GetLastExpense(pref.UserID)
+		if err != nil {
+			return reminders, err
+		}
+
+		if lastExpense == nil {
+			reminders = append(reminders, Reminder{UserID: pref.UserID})
+		} else if now.Sub(lastExpense.CreatedAt) >= window {
+			reminders = append(reminders, Reminder{
+				UserID:       pref.UserID,
+				LastExpense:  lastExpense,
+				DaysInactive: int(now.Sub(lastExpense.CreatedAt).Hours() / 24),
+			})
+		}
+	}
+	return reminders, nil
+}
+
+// MarkReminderSent starts a new reminder window for the user
+func (s *Service) MarkReminderSent(userID uint, sentAt time.Time) error {
+	pref, err := s.repo.GetUserPreference(userID)
+	if err != nil {
+		return err
+	}
+
+	pref.LastReminderAt = &sentAt
+	return s.repo.SaveUserPreference(pref)
+}
+
+// Preferences returns the user's settings
+func (s *Service) Preferences(userID uint) (*models.UserPreference, error) {
+	return s.repo.GetUserPreference(userID)
+}
+
+// SetDailyBudget stores the daily budget used by the digest; 0 removes it
+func (s *Service) SetDailyBudget(userID uint, amount float64) (*models.UserPreference, error) {
+	pref, err := s.repo.GetUserPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pref.DailyBudget = amount
+	if err := s.repo.SaveUserPreference(pref); err != nil {
+		return nil, err
+	}
+	return pref, nil
+}
+
+// SetDailyDigest enables the daily digest at the given "HH:MM" time, or disables it when enabled is false.
+// The caller reschedules the digest job from the returned preferences.
+func (s *Service) SetDailyDigest(userID uint, enabled bool, digestTime string) (*models.UserPreference, error) {
+	if enabled {
+		if _, err := DailyCron(digestTime); err != nil {
+			return nil, ErrInvalidTime
+		}
+	}
+
+	pref, err := s.repo.GetUserPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pref.DigestEnabled = enabled
+	if enabled {
+		pref.DigestTime = digestTime
+	}
+	if err := s.repo.SaveUserPreference(pref); err != nil {
+		return nil, err
+	}
+	return pref, nil
+}
+
+// DigestPreferences returns the preferences of every user with the daily digest enabled
+func (s *Service) DigestPreferences() ([]models.UserPreference, error) {
+	return s.repo.GetDigestPreferences()
+}
+
+// SetInactivityReminder sets after how many days without expenses a reminder is sent (0 disables it)
+func (s *Service) SetInactivityReminder(userID uint, days int) error {
+	pref, err := s.repo.GetUserPreference(userID)
+	if err != nil {
+		return err
+	}
+
+	pref.ReminderDays = days
+	pref.LastReminderAt = nil
+	return s.repo.SaveUserPreference(pref)
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetDailyBudget(t *testing.T) {
+	s, _ := newTestService(t)
+
+	pref, err := s.SetDailyBudget(1, 100000)
+	if err != nil {
+		t.Fatalf("SetDailyBudget: %v", err)
+	}
+	if pref.DailyBudget != 100000 {
+		t.Errorf("DailyBudget = %v, want 100000", pref.DailyBudget)
+	}
+
+	if _, err := s.SetDailyBudget(1, 0); err != nil {
+		t.Fatalf("SetDailyBudget(0): %v", err)
+	}
+	pref, err = s.Preferences(1)
+	if err != nil {
+		t.Fatalf("Preferences: %v", err)
+	}
+	if pref.DailyBudget != 0 {
+		t.Errorf("DailyBudget after removing it = %v, want 0", pref.DailyBudget)
+	}
+}
+
+func TestDailyDigestCoversTheGivenDay(t *testing.T) {
+	s, _ := newTestService(t)
+	if _, err := s.SetDailyBudget(1, 50000); err != nil {
+		t.Fatalf("SetDailyBudget: %v", err)
+	}
+
+	day := time.Date(2026, 10, 18, 21, 0, 0, 0, testLocation)
+	addExpense(t, s, 1, "kopi", "Food", 25000, time.Date(2026, 10, 18, 0, 30, 0, 0, testLocation))
+	addExpense(t, s, 1, "ojek", "Transport", 40000, time.Date(2026, 10, 18, 23, 59, 0, 0, testLocation))
+	// The days before and after, in the service timezone
+	addExpense(t, s, 1, "makan malam", "Food", 70000, time.Date(2026, 10, 17, 23, 0, 0, 0, testLocation))
+	addExpense(t, s, 1, "sarapan", "Food", 10000, time.Date(2026, 10, 19, 0, 0, 0, 0, testLocation))
+
+	digest, err := s.DailyDigest(1, day)
+	if err != nil {
+		t.Fatalf("DailyDigest: %v", err)
+	}
+	if digest.Count != 2 || digest.Total != 65000 {
+		t.Errorf("DailyDigest count, total = %d, %v, want 2, 65000", digest.Count, digest.Total)
+	}
+	if digest.DailyBudget != 50000 {
+		t.Errorf("DailyDigest budget = %v, want 50000", digest.DailyBudget)
+	}
+	if len(digest.Categories) != 2 || digest.Categories[0].Category != "Food" || digest.Categories[1].Category != "Transport" {
+		t.Errorf("DailyDigest categories = %v, want Food then Transport", digest.Categories)
+	}
+	if y, m, d := digest.Date.Date(); y != 2026 || m != time.October || d != 18 {
+		t.Errorf("DailyDigest date = %v, want 18 October 2026", digest.Date)
+	}
+}
+
+func TestSetDailyDigestRejectsInvalidTime(t *testing.T) {
+	s, _ := newTestService(t)
+
+	if _, err := s.SetDailyDigest(1, true, "25:00"); !errors.Is(err, ErrInvalidTime) {
+		t.Errorf("SetDailyDigest(25:00): err = %v, want ErrInvalidTime", err)
+	}
+
+	pref, err := s.SetDailyDigest(1, true, "21:30")
+	if err != nil {
+		t.Fatalf("SetDailyDigest: %v", err)
+	}
+	if !pref.DigestEnabled || pref.DigestTime != "21:30" {
+		t.Errorf("digest = %v at %q, want enabled at 21:30", pref.DigestEnabled, pref.DigestTime)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+// DuplicateWindow is how close in time two identical expenses must be to count as a likely duplicate
+const DuplicateWindow = 10 * time.Minute
+
+// pendingDuplicateTTL is how long the keep/discard question stays answerable
+const pendingDuplicateTTL = time.Hour
+
+// SaveResult is the outcome of SaveExpense. When Duplicate is set, Expense was not saved
+// yet and waits for ResolveDuplicate with Token.
+type SaveResult struct {
+	Expense   *models.Expense
+	Duplicate *models.Expense
+	Token     string
+}
+
+// SaveExpense stores a parsed expense unless it looks like a duplicate of a recent one,
+// in which case it is kept pending until the user decides
+func (s *Service) SaveExpense(expense models.Expense) (*SaveResult, error) {
+	duplicate, err := s.findDuplicateExpense(expense)
+	if err != nil {
+		// Duplicate detection is best effort, never block saving on it
+		log.Printf("Error checking for duplicate expense: %v", err)
+	}
+
+	if duplicate != nil {
+		token, err := newPendingToken()
+		if err == nil {
+			err = s.savePendingConfirmation(expense.UserID, duplicateKey(token), expense, pendingDuplicateTTL)
+		}
+		if err != nil {
+			log.Printf("Error keeping duplicate expense pending: %v", err)
+		} else {
+			return &SaveResult{Expense: &expense, Duplicate: duplicate, Token: token}, nil
+		}
+	}
+
+	if err := s.CreateExpense(&expense); err != nil {
+		return nil, err
+	}
+	return &SaveResult{Expense: &expense}, nil
+}
+
+// ResolveDuplicate handles the answer to a duplicate question: keep saves and returns the pending
+// expense, otherwise it is discarded and nil is returned
+func (s *Service) ResolveDuplicate(userID uint, token string, keep bool) (*models.Expense, error) {
+	var saved *models.Expense
+	err := s.repo.Transaction(func(tx repository.Repository) error {
+		var expense models.Expense
+		if err := takePendingConfirmation(tx, userID, duplicateKey(token), &expense); err != nil {
+			return err
+		}
+		if !keep {
+			return nil
+		}
+
+		if err := tx.CreateExpense(&expense); err != nil {
+			return err
+		}
+		saved = &expense
+		return recordEvents(tx, userID, models.ExpenseCreated, created(&expense))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// duplicateKey is the pending confirmation key of a duplicate question
+func duplicateKey(token string) string {
+	return "duplicate:" + token
+}
+
+// findDuplicateExpense returns a recent expense with the same amount and a similar description, if any
+func (s *Service) findDuplicateExpense(expense models.Expense) (*models.Expense, error) {
+	candidates, err := s.repo.GetRecentExpensesByAmount(expense.UserID, expense.Amount, time.Now().Add(-DuplicateWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range candidates {
+		if similarDescriptions(candidates[i].Description, expense.Description) {
+			return &candidates[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// similarDescriptions compares descriptions by their words, ignoring case and order
+func similarDescriptions(a string, b string) bool {
+	wordsA := strings.Fields(strings.ToLower(a))
+	wordsB := strings.Fields(strings.ToLower(b))
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return len(wordsA) == len(wordsB)
+	}
+
+	set := make(map[string]bool)
+	for _, word := range wordsA {
+		set[word] = true
+	}
+
+	common := 0
+	union := len(set)
+	seen := make(map[string]bool)
+	for _, word := range wordsB {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		if set[word] {
+			common++
+		} else {
+			union++
+		}
+	}
+
+	// Jaccard similarity of the word sets
+	return float64(common)/float64(union) >= 0.5
+}
+
+func newPendingToken() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
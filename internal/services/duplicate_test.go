@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+func TestSaveExpenseAsksAboutLikelyDuplicates(t *testing.T) {
+	s, _ := newTestService(t)
+	first := addExpense(t, s, 1, "kopi susu", "Food", 25000, time.Now())
+
+	result, err := s.SaveExpense(models.Expense{UserID: 1, Description: "Susu kopi", Category: "Food", Amount: 25000, Date: time.Now()})
+	if err != nil {
+		t.Fatalf("SaveExpense: %v", err)
+	}
+	if result.Duplicate == nil || result.Duplicate.ID != first.ID || result.Token == "" {
+		t.Fatalf("SaveExpense = %+v, want a duplicate question about expense %d", result, first.ID)
+	}
+
+	// A different amount or description is saved right away
+	for _, expense := range []models.Expense{
+		{UserID: 1, Description: "kopi susu", Category: "Food", Amount: 30000, Date: time.Now()},
+		{UserID: 1, Description: "parkir", Category: "Transport", Amount: 25000, Date: time.Now()},
+	} {
+		result, err := s.SaveExpense(expense)
+		if err != nil {
+			t.Fatalf("SaveExpense(%s %v): %v", expense.Description, expense.Amount, err)
+		}
+		if result.Duplicate != nil || result.Expense.ID == 0 {
+			t.Errorf("SaveExpense(%s %v) asked about a duplicate", expense.Description, expense.Amount)
+		}
+	}
+}
+
+func TestResolveDuplicate(t *testing.T) {
+	for _, keep := range []bool{true, false} {
+		s, _ := newTestService(t)
+		addExpense(t, s, 1, "kopi", "Food", 25000, time.Now())
+
+		result, err := s.SaveExpense(models.Expense{UserID: 1, Description: "kopi", Category: "Food", Amount: 25000, Date: time.Now()})
+		if err != nil || result.Duplicate == nil {
+			t.Fatalf("SaveExpense = %+v, %v, want a duplicate question", result, err)
+		}
+
+		if _, err := s.ResolveDuplicate(2, result.Token, keep); !errors.Is(err, ErrExpired) {
+			t.Errorf("ResolveDuplicate by another user: err = %v, want ErrExpired", err)
+		}
+
+		saved, err := s.ResolveDuplicate(1, result.Token, keep)
+		if err != nil {
+			t.Fatalf("ResolveDuplicate(keep=%v): %v", keep, err)
+		}
+		if keep != (saved != nil) {
+			t.Errorf("ResolveDuplicate(keep=%v) saved = %v", keep, saved)
+		}
+
+		expenses, err := s.ListExpenses(1, repository.ExpenseFilter{})
+		if err != nil {
+			t.Fatalf("ListExpenses: %v", err)
+		}
+		want := 1
+		if keep {
+			want = 2
+		}
+		if len(expenses) != want {
+			t.Errorf("ResolveDuplicate(keep=%v) left %d expenses, want %d", keep, len(expenses), want)
+		}
+
+		// Each question is answered once
+		if _, err := s.ResolveDuplicate(1, result.Token, true); !errors.Is(err, ErrExpired) {
+			t.Errorf("second ResolveDuplicate: err = %v, want ErrExpired", err)
+		}
+	}
+}
+
+func TestSimilarDescriptions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"kopi susu", "Susu Kopi", true},
+		{"kopi susu gula aren", "kopi susu", true},
+		{"kopi", "teh", false},
+		{"makan siang kantor", "makan malam", false},
+		{"", "", true},
+		{"kopi", "", false},
+	}
+	for _, tt := range tests {
+		if got := similarDescriptions(tt.a, tt.b); got != tt.want {
+			t.Errorf("similarDescriptions(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+// Fields of an expense the guided edit can change
+const (
+	EditDescription = "description"
+	EditAmount      = "amount"
+	EditCategory    = "category"
+	EditDate        = "date"
+	EditAccount     = "account"
+)
+
+// EditFields lists the editable fields in the order they are offered
+var EditFields = []string{EditDescription, EditAmount, EditCategory, EditDate, EditAccount}
+
+// ErrInvalidEditValue is returned when a value sent during a guided edit cannot be used for its field
+var ErrInvalidEditValue = errors.New("invalid value")
+
+// IsEditField reports whether field is one of EditFields
+func IsEditField(field string) bool {
+	for _, editable := range EditFields {
+		if editable == field {
+			return true
+		}
+	}
+	return false
+}
+
+// EditExpenseField sets one field of an expense from the text the user sent. It returns
+// ErrInvalidEditValue when the text does not fit the field and repository.ErrNotFound
+// when the user has no such expense.
+func (s *Service) EditExpenseField(userID uint, expenseID uint, field string, text string) (*models.Expense, error) {
+	expense, err := s.repo.GetExpenseByID(userID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := models.SnapshotOf(expense)
+	text = strings.TrimSpace(text)
+	switch field {
+	case EditDescription, EditCategory:
+		if text == "" {
+			return nil, ErrInvalidEditValue
+		}
+		if field == EditDescription {
+			expense.Description = text
+		} else {
+			expense.Category = text
+		}
+	case EditAmount:
+		amount, ok := parseAmountWord(strings.ReplaceAll(text, " ", ""))
+		if !ok {
+			return nil, ErrInvalidEditValue
+		}
+		expense.Amount = amount
+	case EditDate:
+		date, ok := s.parseEditDate(text, expense.Date)
+		if !ok {
+			return nil, ErrInvalidEditValue
+		}
+		expense.Date = date
+	case EditAccount:
+		// A dash clears the account
+		if text == "-" {
+			text = ""
+		}
+		expense.Account = text
+	default:
+		return nil, ErrInvalidEditValue
+	}
+
+	if err := s.saveUpdate(before, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+// parseEditDate reads "hari ini", "kemarin", YYYY-MM-DD, DD/MM/YYYY or DD/MM (this year).
+// The time of day of the current date is kept.
+func (s *Service) parseEditDate(text string, current time.Time) (time.Time, bool) {
+	now := time.Now().In(s.location)
+	var day time.Time
+	switch strings.ToLower(text) {
+	case "hari ini", "today":
+		day = now
+	case "kemarin", "yesterday":
+		day = now.AddDate(0, 0, -1)
+	default:
+		parsed := false
+		for _, layout := range []string{"2006-01-02", "2/1/2006", "2-1-2006", "2/1"} {
+			if date, err := time.ParseInLocation(layout, text, s.location); err == nil {
+				if layout == "2/1" {
+					date = date.AddDate(now.Year(), 0, 0)
+				}
+				day, parsed = date, true
+				break
+			}
+		}
+		if !parsed {
+			return time.Time{}, false
+		}
+	}
+
+	clock := current.In(s.location)
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, s.location), true
+}
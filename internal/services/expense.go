@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+// ParseExpense extracts an expense from a natural language message; #words become tags
+func (s *Service) ParseExpense(ctx context.Context, text string) (models.Expense, error) {
+	// Keep the tags out of the description the AI writes
+	var words []string
+	for _, word := range strings.Fields(text) {
+		if !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+
+	expense, err := s.ai.ParseExpense(ctx, strings.Join(words, " "), time.Now().In(s.location))
+	if err != nil {
+		return expense, err
+	}
+	expense.Tags = models.HashTags(text)
+	return expense, nil
+}
+
+func (s *Service) CreateExpense(expense *models.Expense) error {
+	return s.repo.Transaction(func(tx repository.Repository) error {
+		if err := tx.CreateExpense(expense); err != nil {
+			return err
+		}
+		return recordEvents(tx, expense.UserID, models.ExpenseCreated, created(expense))
+	})
+}
+
+// GetExpense returns repository.ErrNotFound when the user has no such expense
+func (s *Service) GetExpense(userID uint, expenseID uint) (*models.Expense, error) {
+	return s.repo.GetExpenseByID(userID, expenseID)
+}
+
+// UpdateExpense saves the changed fields of an existing expense
+func (s *Service) UpdateExpense(expense *models.Expense) error {
+	current, err := s.repo.GetExpenseByID(expense.UserID, expense.ID)
+	if err != nil {
+		return err
+	}
+	return s.saveUpdate(models.SnapshotOf(current), expense)
+}
+
+func (s *Service) saveUpdate(before *models.ExpenseSnapshot, expense *models.Expense) error {
+	return s.repo.Transaction(func(tx repository.Repository) error {
+		if err := tx.UpdateExpense(expense); err != nil {
+			return err
+		}
+		return recordEvents(tx, expense.UserID, models.ExpenseUpdated, expenseChange{expenseID: expense.ID, before: before, after: models.SnapshotOf(expense)})
+	})
+}
+
+// DeleteExpense soft-deletes an expense so it can be restored later. It returns
+// repository.ErrNotFound when the user has no such expense.
+func (s *Service) DeleteExpense(userID uint, expenseID uint) error {
+	expense, err := s.repo.GetExpenseByID(userID, expenseID)
+	if err != nil {
+		return err
+	}
+	return s.repo.Transaction(func(tx repository.Repository) error {
+		if err := tx.DeleteExpense(userID, expenseID); err != nil {
+			return err
+		}
+		return recordEvents(tx, userID, models.ExpenseDeleted, expenseChange{expenseID: expenseID, before: models.SnapshotOf(expense)})
+	})
+}
+
+// listPageSize is how many expenses one page of /lihat shows
+const listPageSize = 10
+
+// ExpensePage is one page of the user's expenses, newest first
+type ExpensePage struct {
+	Expenses []models.Expense
+	HasNewer bool
+	HasOlder bool
+}
+
+// BrowseExpenses returns the page of the user's expenses older than the cursor, or newer when newer is set.
+// A nil cursor returns the latest expenses. When the cursor has no expenses left, for example after
+// deleting the last ones, the latest page is returned instead.
+func (s *Service) BrowseExpenses(userID uint, cursor *repository.ExpenseCursor, newer bool) (*ExpensePage, error) {
+	// One extra row tells whether there is another page in the same direction
+	expenses, err := s.repo.ListExpensesByCursor(userID, cursor, newer, listPageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(expenses) == 0 && cursor != nil {
+		return s.BrowseExpenses(userID, nil, false)
+	}
+
+	page := &ExpensePage{Expenses: expenses}
+	more := len(expenses) > listPageSize
+	if cursor != nil && newer {
+		if more {
+			page.Expenses = expenses[1:]
+		}
+		page.HasNewer = more
+		page.HasOlder = true
+	} else {
+		if more {
+			page.Expenses = expenses[:listPageSize]
+		}
+		page.HasOlder = more
+		page.HasNewer = cursor != nil
+	}
+	return page, nil
+}
+
+// ListExpenses returns the user's expenses matching the filter, newest first
+func (s *Service) ListExpenses(userID uint, filter repository.ExpenseFilter) ([]models.Expense, error) {
+	return s.repo.GetExpensesFiltered(userID, filter)
+}
+
+// ListExpensesPage returns one page of the user's expenses along with the total count.
+// sortColumn must be a value of repository.ExpenseSortColumns.
+func (s *Service) ListExpensesPage(userID uint, filter repository.ExpenseFilter, sortColumn string, descending bool, limit int, offset int) ([]models.Expense, int64, error) {
+	return s.repo.ListExpensesPage(userID, filter, sortColumn, descending, limit, offset)
+}
+
+// CategoryTotals returns the number and sum of the user's expenses per category
+func (s *Service) CategoryTotals(userID uint, filter repository.ExpenseFilter) ([]repository.CategoryTotal, error) {
+	return s.repo.GetCategoryTotals(userID, filter)
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+func TestExpenseCRUD(t *testing.T) {
+	s, _ := newTestService(t)
+	created := addExpense(t, s, 1, "kopi", "Food", 25000, time.Now())
+	if created.ID == 0 {
+		t.Fatal("CreateExpense did not assign an ID")
+	}
+
+	got, err := s.GetExpense(1, created.ID)
+	if err != nil {
+		t.Fatalf("GetExpense: %v", err)
+	}
+	if got.Description != "kopi" || got.Amount != 25000 {
+		t.Errorf("GetExpense = %q %v, want kopi 25000", got.Description, got.Amount)
+	}
+
+	got.Amount = 30000
+	got.Category = "Drinks"
+	if err := s.UpdateExpense(got); err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	got, err = s.GetExpense(1, created.ID)
+	if err != nil {
+		t.Fatalf("GetExpense after update: %v", err)
+	}
+	if got.Amount != 30000 || got.Category != "Drinks" {
+		t.Errorf("after update = %v %q, want 30000 Drinks", got.Amount, got.Category)
+	}
+
+	if err := s.DeleteExpense(1, created.ID); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+	if _, err := s.GetExpense(1, created.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetExpense after delete: err = %v, want ErrNotFound", err)
+	}
+
+	restored, err := s.RestoreExpense(1, created.ID)
+	if err != nil {
+		t.Fatalf("RestoreExpense: %v", err)
+	}
+	if restored.ID != created.ID || restored.Amount != 30000 {
+		t.Errorf("RestoreExpense = %d %v, want %d 30000", restored.ID, restored.Amount, created.ID)
+	}
+
+	events, err := s.ExpenseHistory(1, created.ID)
+	if err != nil {
+		t.Fatalf("ExpenseHistory: %v", err)
+	}
+	var actions []string
+	for _, event := range events {
+		actions = append(actions, event.Action)
+	}
+	want := []string{models.ExpenseCreated, models.ExpenseUpdated, models.ExpenseDeleted, models.ExpenseRestored}
+	if len(actions) != len(want) {
+		t.Fatalf("history actions = %v, want %v", actions, want)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("history actions = %v, want %v", actions, want)
+			break
+		}
+	}
+}
+
+func TestExpensesOfOtherUsersAreNotFound(t *testing.T) {
+	s, _ := newTestService(t)
+	expense := addExpense(t, s, 1, "kopi", "Food", 25000, time.Now())
+
+	if _, err := s.GetExpense(2, expense.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetExpense by another user: err = %v, want ErrNotFound", err)
+	}
+	if err := s.DeleteExpense(2, expense.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("DeleteExpense by another user: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.GetExpense(1, expense.ID); err != nil {
+		t.Errorf("expense is gone after another user tried to delete it: %v", err)
+	}
+}
+
+func TestUndoRevertsChangesNewestFirst(t *testing.T) {
+	s, _ := newTestService(t)
+	expense := addExpense(t, s, 1, "kopi", "Food", 25000, time.Now())
+
+	expense.Amount = 30000
+	if err := s.UpdateExpense(expense); err != nil {
+		t.Fatalf("UpdateExpense: %v", err)
+	}
+	if err := s.DeleteExpense(1, expense.ID); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+
+	steps := []struct {
+		action string
+		exists bool
+		amount float64
+	}{
+		{models.ExpenseDeleted, true, 30000},
+		{models.ExpenseUpdated, true, 25000},
+		{models.ExpenseCreated, false, 0},
+	}
+	for _, step := range steps {
+		result, err := s.Undo(1)
+		if err != nil {
+			t.Fatalf("Undo %s: %v", step.action, err)
+		}
+		if result.Action != step.action {
+			t.Errorf("Undo action = %s, want %s", result.Action, step.action)
+		}
+
+		got, err := s.GetExpense(1, expense.ID)
+		if !step.exists {
+			if !errors.Is(err, repository.ErrNotFound) {
+				t.Errorf("after undoing %s: err = %v, want ErrNotFound", step.action, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("after undoing %s: %v", step.action, err)
+		}
+		if got.Amount != step.amount {
+			t.Errorf("after undoing %s: amount = %v, want %v", step.action, got.Amount, step.amount)
+		}
+	}
+
+	if _, err := s.Undo(1); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo with nothing left: err = %v, want ErrNothingToUndo", err)
+	}
+}
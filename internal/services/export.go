@@ -0,0 +1,61 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"SmartExpenseAI/internal/export"
+	"SmartExpenseAI/internal/i18n"
+	"SmartExpenseAI/internal/repository"
+)
+
+// ExportFile is one rendered export
+type ExportFile struct {
+	Format string
+	Name   string
+	Data   []byte
+}
+
+// ExportResult holds the rendered files of an export along with what they contain
+type ExportResult struct {
+	Files []ExportFile
+	// Failed lists the formats that could not be rendered
+	Failed []string
+	Count  int
+	Total  float64
+}
+
+// ExportExpenses renders the user's expenses matching the filter in each requested format
+func (s *Service) ExportExpenses(userID uint, formats []string, filter repository.ExpenseFilter) (*ExportResult, error) {
+	expenses, err := s.repo.GetExpensesFiltered(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExportResult{Count: len(expenses)}
+	if len(expenses) == 0 {
+		return result, nil
+	}
+
+	for _, expense := range expenses {
+		result.Total += expense.Amount
+	}
+
+	lang, err := s.Language(userID)
+	if err != nil {
+		log.Printf("Error reading language of user %d for export: %v", userID, err)
+	}
+	printer := i18n.NewPrinter(lang, s.location)
+
+	now := time.Now()
+	for _, format := range formats {
+		data, err := export.Render(format, expenses, printer)
+		if err != nil {
+			log.Printf("Error rendering %s export: %v", format, err)
+			result.Failed = append(result.Failed, format)
+			continue
+		}
+		result.Files = append(result.Files, ExportFile{Format: format, Name: export.FileName(format, now, printer), Data: data})
+	}
+	return result, nil
+}
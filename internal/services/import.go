@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"SmartExpenseAI/internal/importer"
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+// ErrUnreadableStatement is returned when a file matches none of the supported statement formats
+var ErrUnreadableStatement = errors.New("unsupported statement format")
+
+// categorizeBatchSize bounds how many descriptions are sent to the AI at once
+const categorizeBatchSize = 50
+
+// pendingImportTTL is how long a previewed import can still be confirmed
+const pendingImportTTL = 30 * time.Minute
+
+// pendingImport is the previewed import kept as a pending confirmation
+type pendingImport struct {
+	Format string
+	Rows   []importer.Row
+}
+
+// ImportPreview is the dry run of an import; nothing is saved until ConfirmImport
+type ImportPreview struct {
+	Format         string
+	Rows           []importer.Row
+	NewCount       int
+	DuplicateCount int
+	// Total is the sum of the new rows
+	Total float64
+}
+
+// ImportResult describes a saved import
+type ImportResult struct {
+	Format string
+	Count  int
+	Total  float64
+}
+
+// MaxImportFileSize is the largest statement file accepted for import
+func (s *Service) MaxImportFileSize() int64 {
+	return s.maxImportFileSize
+}
+
+// PreviewImport parses a bank statement, categorizes and deduplicates its rows, and keeps
+// the result pending until the user confirms it
+func (s *Service) PreviewImport(ctx context.Context, userID uint, data []byte) (*ImportPreview, error) {
+	format, rows, err := importer.Parse(data, time.Now().In(s.location))
+	if err != nil {
+		log.Printf("Error parsing import file: %v", err)
+		return nil, ErrUnreadableStatement
+	}
+
+	preview := &ImportPreview{Format: format, Rows: rows}
+	if len(rows) == 0 {
+		return preview, nil
+	}
+
+	s.categorizeRows(ctx, rows)
+
+	if err := s.markDuplicateRows(userID, rows); err != nil {
+		return nil, fmt.Errorf("failed to check duplicates: %w", err)
+	}
+
+	for _, row := range rows {
+		if row.Duplicate {
+			preview.DuplicateCount++
+			continue
+		}
+		preview.NewCount++
+		preview.Total += row.Amount
+	}
+
+	if err := s.savePendingConfirmation(userID, importKey(userID), pendingImport{Format: format, Rows: rows}, pendingImportTTL); err != nil {
+		return nil, fmt.Errorf("failed to keep import pending: %w", err)
+	}
+	return preview, nil
+}
+
+// ConfirmImport saves the new rows of the user's previewed import
+func (s *Service) ConfirmImport(userID uint) (*ImportResult, error) {
+	var result *ImportResult
+	err := s.repo.Transaction(func(tx repository.Repository) error {
+		var pending pendingImport
+		if err := takePendingConfirmation(tx, userID, importKey(userID), &pending); err != nil {
+			return err
+		}
+
+		var expenses []models.Expense
+		result = &ImportResult{Format: pending.Format}
+		for _, row := range pending.Rows {
+			if row.Duplicate {
+				continue
+			}
+			expenses = append(expenses, models.Expense{
+				UserID:      userID,
+				Description: row.Description,
+				Category:    row.Category,
+				Account:     pending.Format,
+				Amount:      row.Amount,
+				Date:        row.Date,
+			})
+			result.Total += row.Amount
+		}
+		result.Count = len(expenses)
+
+		if len(expenses) == 0 {
+			return nil
+		}
+		if err := tx.CreateExpenses(expenses); err != nil {
+			return err
+		}
+		changes := make([]expenseChange, 0, len(expenses))
+		for i := range expenses {
+			changes = append(changes, created(&expenses[i]))
+		}
+		return recordEvents(tx, userID, models.ExpenseCreated, changes...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CancelImport discards the user's previewed import
+func (s *Service) CancelImport(userID uint) error {
+	var pending pendingImport
+	err := takePendingConfirmation(s.repo, userID, importKey(userID), &pending)
+	if errors.Is(err, ErrExpired) {
+		return nil
+	}
+	return err
+}
+
+// importKey is the pending confirmation key of a user's previewed import; a new preview
+// replaces the previous one
+func importKey(userID uint) string {
+	return fmt.Sprintf("import:%d", userID)
+}
+
+// categorizeRows fills in missing categories using keyword rules first, then the AI
+func (s *Service) categorizeRows(ctx context.Context, rows []importer.Row) {
+	var uncategorized []int
+	for i := range rows {
+		if rows[i].Category != "" {
+			continue
+		}
+		if category := importer.CategorizeByRules(rows[i].Description); category != "" {
+			rows[i].Category = category
+			continue
+		}
+		uncategorized = append(uncategorized, i)
+	}
+
+	for start := 0; start < len(uncategorized); start += categorizeBatchSize {
+		end := start + categorizeBatchSize
+		if end > len(uncategorized) {
+			end = len(uncategorized)
+		}
+
+		descriptions := make([]string, 0, end-start)
+		for _, i := range uncategorized[start:end] {
+			descriptions = append(descriptions, rows[i].Description)
+		}
+
+		categories, err := s.ai.CategorizeDescriptions(ctx, descriptions)
+		if err != nil {
+			log.Printf("Error categorizing imported rows: %v", err)
+		}
+		for j, i := range uncategorized[start:end] {
+			if j < len(categories) {
+				rows[i].Category = categories[j]
+			}
+		}
+	}
+
+	for i := range rows {
+		if rows[i].Category == "" {
+			rows[i].Category = "Lainnya"
+		}
+	}
+}
+
+// markDuplicateRows flags rows the user already has stored: an expense with the same amount on
+// the same day and a similar description. Each stored expense matches at most one row, so
+// identical rows within the statement, such as two coffees on one day, are all kept unless
+// they were stored before.
+func (s *Service) markDuplicateRows(userID uint, rows []importer.Row) error {
+	matched := make(map[uint]bool)
+	for i := range rows {
+		local := rows[i].Date.In(s.location)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.location)
+		candidates, err := s.repo.GetExpensesByAmount(userID, rows[i].Amount, day, day.AddDate(0, 0, 1))
+		if err != nil {
+			return err
+		}
+
+		for j := range candidates {
+			if matched[candidates[j].ID] || !similarDescriptions(candidates[j].Description, rows[i].Description) {
+				continue
+			}
+			matched[candidates[j].ID] = true
+			rows[i].Duplicate = true
+			rows[i].DuplicateOf = &candidates[j]
+			break
+		}
+	}
+	return nil
+}
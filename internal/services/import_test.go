@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/repository"
+)
+
+const testStatement = "Tanggal,Deskripsi,Jumlah,Kategori\n" +
+	"2026-10-01,Indomaret,15000,Belanja\n" +
+	"2026-10-02,Grab,20000,Transport\n" +
+	"2026-10-02,Grab,20000,Transport\n"
+
+func TestImportPreviewConfirmAndUndo(t *testing.T) {
+	s, _ := newTestService(t)
+	addExpense(t, s, 1, "Indomaret", "Belanja", 15000, time.Date(2026, 10, 1, 12, 0, 0, 0, testLocation))
+
+	preview, err := s.PreviewImport(context.Background(), 1, []byte(testStatement))
+	if err != nil {
+		t.Fatalf("PreviewImport: %v", err)
+	}
+	// The first row is already stored; the two identical Grab rows are separate rides
+	if preview.NewCount != 2 || preview.DuplicateCount != 1 || preview.Total != 40000 {
+		t.Errorf("preview new, duplicates, total = %d, %d, %v, want 2, 1, 40000", preview.NewCount, preview.DuplicateCount, preview.Total)
+	}
+
+	result, err := s.ConfirmImport(1)
+	if err != nil {
+		t.Fatalf("ConfirmImport: %v", err)
+	}
+	if result.Count != 2 || result.Total != 40000 {
+		t.Errorf("ConfirmImport count, total = %d, %v, want 2, 40000", result.Count, result.Total)
+	}
+	if _, err := s.ConfirmImport(1); !errors.Is(err, ErrExpired) {
+		t.Errorf("second ConfirmImport: err = %v, want ErrExpired", err)
+	}
+
+	undo, err := s.Undo(1)
+	if err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if len(undo.Expenses) != 2 || undo.Expenses[0].Description != "Grab" || undo.Expenses[1].Description != "Grab" {
+		t.Errorf("Undo reverted %v, want the two imported Grab expenses", undo.Expenses)
+	}
+	expenses, err := s.ListExpenses(1, repository.ExpenseFilter{})
+	if err != nil {
+		t.Fatalf("ListExpenses: %v", err)
+	}
+	if len(expenses) != 1 {
+		t.Errorf("after undoing the import %d expenses are left, want 1", len(expenses))
+	}
+}
+
+func TestImportPreviewDuplicates(t *testing.T) {
+	s, _ := newTestService(t)
+	stored := addExpense(t, s, 1, "Kopi Kenangan", "Makanan", 25000, time.Date(2026, 10, 5, 8, 0, 0, 0, testLocation))
+	addExpense(t, s, 1, "Parkir", "Transport", 5000, time.Date(2026, 10, 5, 9, 0, 0, 0, testLocation))
+
+	statement := "Tanggal,Deskripsi,Jumlah,Kategori\n" +
+		// Two coffees that day, one of them already logged
+		"2026-10-05,Kopi Kenangan,25000,Makanan\n" +
+		"2026-10-05,Kopi Kenangan,25000,Makanan\n" +
+		// Same amount and day as the parking fee, but something else
+		"2026-10-05,Indomaret,5000,Belanja\n" +
+		// Same expense on another day
+		"2026-10-06,Kopi Kenangan,25000,Makanan\n"
+
+	preview, err := s.PreviewImport(context.Background(), 1, []byte(statement))
+	if err != nil {
+		t.Fatalf("PreviewImport: %v", err)
+	}
+
+	var duplicates []bool
+	for _, row := range preview.Rows {
+		duplicates = append(duplicates, row.Duplicate)
+	}
+	if want := []bool{true, false, false, false}; !slices.Equal(duplicates, want) {
+		t.Errorf("duplicate rows = %v, want %v", duplicates, want)
+	}
+	if dup := preview.Rows[0].DuplicateOf; dup == nil || dup.ID != stored.ID {
+		t.Errorf("first row repeats %v, want expense %d", dup, stored.ID)
+	}
+	if preview.NewCount != 3 || preview.DuplicateCount != 1 {
+		t.Errorf("preview new, duplicates = %d, %d, want 3, 1", preview.NewCount, preview.DuplicateCount)
+	}
+}
+
+func TestCancelImport(t *testing.T) {
+	s, _ := newTestService(t)
+
+	if _, err := s.PreviewImport(context.Background(), 1, []byte(testStatement)); err != nil {
+		t.Fatalf("PreviewImport: %v", err)
+	}
+	if err := s.CancelImport(1); err != nil {
+		t.Fatalf("CancelImport: %v", err)
+	}
+	if _, err := s.ConfirmImport(1); !errors.Is(err, ErrExpired) {
+		t.Errorf("ConfirmImport after cancelling: err = %v, want ErrExpired", err)
+	}
+	// Cancelling without a pending import is not an error
+	if err := s.CancelImport(1); err != nil {
+		t.Errorf("second CancelImport: %v", err)
+	}
+}
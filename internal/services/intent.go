@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Intents a plain-text message can be classified as
+const (
+	IntentExpense  = "expense"
+	IntentList     = "list"
+	IntentMonthly  = "monthly"
+	IntentWeekly   = "weekly"
+	IntentDelete   = "delete"
+	IntentUpdate   = "update"
+	IntentBudget   = "budget"
+	IntentHelp     = "help"
+	IntentQuestion = "question"
+)
+
+// Intent is what a plain-text message asks for. ExpenseID is set for deletes and updates,
+// Amount for budgets.
+type Intent struct {
+	Kind      string
+	ExpenseID uint
+	Amount    float64
+	// Missing is set when the intent is clear but a required detail, such as the ID, is not
+	Missing bool
+}
+
+// commandKeywords hint that a message is a command rather than an expense
+var commandKeywords = []string{
+	"lihat", "tampilkan", "pengeluaranku", "daftar", "list",
+	"rekap", "ringkasan", "summary", "bulan", "minggu",
+	"hapus", "delete", "remove", "bantuan", "help",
+	"apa yang", "perintah", "commands", "cara", "histori", "semua",
+	"terakhir", "pengeluaran", "my expenses", "show expenses",
+	"ubah", "ganti", "edit", "koreksi", "anggaran", "budget",
+}
+
+// The command rules match the whole message, so that an expense which merely mentions a
+// command word, such as "hotel budget 300rb" or "bayar tutor les 150rb", is not taken for one.
+var (
+	questionPattern     = regexp.MustCompile(`(?i)^(?:berapa|kapan|apakah|apa saja|di ?mana|mana|paling|seberapa)\b`)
+	updatePattern       = regexp.MustCompile(`(?i)^(?:tolong\s+)?(?:ubah|ganti|update|edit|koreksi|perbaiki)\s+(?:pengeluaran\s+|data\s+)?(?:id\s*|no\.?\s*|nomor\s+|#)?(\d+)\b`)
+	deletePattern       = regexp.MustCompile(`(?i)^(?:tolong\s+)?(?:hapus|delete|remove)(?:\s+(?:pengeluaran|data|expense))?(?:\s+(?:id\s*|no\.?\s*|nomor\s+|#)?(\d+))?[\s.!]*$`)
+	budgetPattern       = regexp.MustCompile(`(?i)^(?:tolong\s+)?(?:(?:set|atur|ubah|ganti|pasang)\s+(?:anggaran|budget)(?:\s+harian)?|(?:anggaran|budget)\s+harian|daily\s+budget)(?:\s+(?:jadi|menjadi|ke|=|:))?(?:\s+(.*?))?[\s.!]*$`)
+	budgetClearPattern  = regexp.MustCompile(`(?i)^(?:tolong\s+)?(?:hapus|matikan)\s+(?:anggaran|budget)(?:\s+harian)?[\s.!]*$`)
+	helpPattern         = regexp.MustCompile(`(?i)^(?:bantuan|help|menu|perintah|daftar perintah|commands|cara (?:pakai|guna|menggunakan)(?: bot)?)[\s?!.]*$`)
+	amountSuffixPattern = regexp.MustCompile(`(?i)(\d)\s+(k|rb|ribu|jt|juta)\b`)
+)
+
+// budgetClearWords turn the daily budget off when they follow "anggaran harian"
+var budgetClearWords = map[string]bool{"off": true, "hapus": true, "matikan": true, "0": true, "nol": true}
+
+// ClassifyIntent decides whether a plain-text message logs an expense or asks for a command.
+// Keyword rules are tried first; the AI is only asked when the rules see command words they
+// cannot place.
+func (s *Service) ClassifyIntent(ctx context.Context, text string) Intent {
+	if intent, ok := classifyByRules(text); ok {
+		return intent
+	}
+	if !containsWords(text, commandKeywords) || !s.ai.Configured() {
+		return Intent{Kind: IntentExpense}
+	}
+
+	classified, err := s.ai.ClassifyIntent(ctx, text)
+	if err != nil {
+		log.Printf("Error classifying message intent: %v", err)
+		return Intent{Kind: IntentExpense}
+	}
+	return classified.normalize()
+}
+
+// normalize fills Missing for intents that need details the AI did not find
+func (i Intent) normalize() Intent {
+	switch i.Kind {
+	case IntentDelete, IntentUpdate:
+		i.Missing = i.ExpenseID == 0
+	case IntentBudget:
+		// Only the rules turn the budget off; an AI guess without an amount asks for one
+		i.Missing = i.Amount <= 0
+	case IntentList, IntentMonthly, IntentWeekly, IntentHelp, IntentQuestion:
+	default:
+		i.Kind = IntentExpense
+	}
+	return i
+}
+
+// classifyByRules recognizes the usual phrasings of each command
+func classifyByRules(text string) (Intent, bool) {
+	lowerText := strings.ToLower(strings.TrimSpace(text))
+
+	// Updates only name the expense; the new values are asked for by the guided edit
+	if match := updatePattern.FindStringSubmatch(strings.TrimSpace(text)); match != nil {
+		id, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			return Intent{Kind: IntentUpdate, Missing: true}, true
+		}
+		return Intent{Kind: IntentUpdate, ExpenseID: uint(id)}, true
+	}
+
+	if questionPattern.MatchString(lowerText) {
+		return Intent{Kind: IntentQuestion}, true
+	}
+
+	if budgetClearPattern.MatchString(lowerText) {
+		return Intent{Kind: IntentBudget}, true
+	}
+	if match := budgetPattern.FindStringSubmatch(lowerText); match != nil {
+		value := strings.TrimSpace(amountSuffixPattern.ReplaceAllString(match[1], "$1$2"))
+		if value == "" {
+			return Intent{Kind: IntentBudget, Missing: true}, true
+		}
+		if budgetClearWords[value] {
+			return Intent{Kind: IntentBudget}, true
+		}
+		if amount, ok := parseAmountWord(value); ok {
+			return Intent{Kind: IntentBudget, Amount: amount}, true
+		}
+		// Anything else after the budget words is left to the AI
+		return Intent{}, false
+	}
+
+	if containsWords(lowerText, []string{
+		"lihat pengeluaran", "tampilkan pengeluaran", "pengeluaranku", "daftar pengeluaran",
+		"list pengeluaran", "show expenses", "my expenses", "lihat rekap", "lihat daftar",
+		"tampilkan daftar", "daftar terakhir", "lihat terakhir", "pengeluaran terakhir",
+		"lihat semua", "tampilkan semua", "lihat histori", "tampilkan histori"}) {
+		return Intent{Kind: IntentList}, true
+	}
+
+	if containsWords(lowerText, []string{"rekap bulan", "pengeluaran bulan", "ringkasan bulan", "summary bulan", "monthly recap", "month summary"}) {
+		return Intent{Kind: IntentMonthly}, true
+	}
+
+	if match := deletePattern.FindStringSubmatch(lowerText); match != nil {
+		intent := Intent{Kind: IntentDelete}
+		if id, err := strconv.ParseUint(match[1], 10, 32); err == nil {
+			intent.ExpenseID = uint(id)
+		}
+		return intent.normalize(), true
+	}
+
+	if helpPattern.MatchString(lowerText) {
+		return Intent{Kind: IntentHelp}, true
+	}
+
+	if containsWords(lowerText, []string{"rekap minggu", "pengeluaran minggu", "ringkasan minggu", "summary minggu"}) {
+		return Intent{Kind: IntentWeekly}, true
+	}
+
+	if strings.HasSuffix(lowerText, "?") {
+		return Intent{Kind: IntentQuestion}, true
+	}
+
+	return Intent{}, false
+}
+
+// parseAmountWord reads rupiah amounts such as 25000, 75.000, Rp50.000, 50k, 30rb or 1,5jt
+func parseAmountWord(word string) (float64, bool) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	word = strings.TrimPrefix(strings.TrimPrefix(word, "rp."), "rp")
+
+	multiplier := 1.0
+	for _, suffix := range []struct {
+		text  string
+		value float64
+	}{{"ribu", 1e3}, {"rb", 1e3}, {"k", 1e3}, {"juta", 1e6}, {"jt", 1e6}} {
+		if strings.HasSuffix(word, suffix.text) {
+			word = strings.TrimSuffix(word, suffix.text)
+			multiplier = suffix.value
+			break
+		}
+	}
+	if word == "" || word[0] < '0' || word[0] > '9' {
+		return 0, false
+	}
+
+	if multiplier > 1 {
+		// With a suffix the separator is a decimal point, as in 1,5jt
+		word = strings.ReplaceAll(word, ",", ".")
+	} else {
+		// Without one it separates thousands, as in 75.000
+		word = strings.NewReplacer(".", "", ",", "").Replace(word)
+	}
+
+	amount, err := strconv.ParseFloat(word, 64)
+	if err != nil || amount <= 0 {
+		return 0, false
+	}
+	return amount * multiplier, true
+}
+
+// containsWords checks if the text contains any of the keywords as whole words, so that
+// "help" does not match "helper" nor "off" match "coffee"
+func containsWords(text string, keywords []string) bool {
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ") + " "
+	for _, keyword := range keywords {
+		if strings.Contains(words, " "+keyword+" ") {
+			return true
+		}
+	}
+	return false
+}
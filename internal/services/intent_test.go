@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClassifyByRules(t *testing.T) {
+	tests := []struct {
+		text string
+		want Intent
+		ok   bool
+	}{
+		// Expenses that mention a command word are left alone
+		{"hotel budget 300rb", Intent{}, false},
+		{"bayar tutor les 150rb", Intent{}, false},
+		{"kopi 25rb", Intent{}, false},
+		{"coffee off 20k", Intent{}, false},
+		{"makan siang di warung 35000", Intent{}, false},
+
+		{"ubah 12", Intent{Kind: IntentUpdate, ExpenseID: 12}, true},
+		{"Tolong edit pengeluaran #7", Intent{Kind: IntentUpdate, ExpenseID: 7}, true},
+		{"koreksi no. 3 jadi 50rb", Intent{Kind: IntentUpdate, ExpenseID: 3}, true},
+		{"ganti id 99999999999", Intent{Kind: IntentUpdate, Missing: true}, true},
+
+		{"hapus 5", Intent{Kind: IntentDelete, ExpenseID: 5}, true},
+		{"hapus pengeluaran nomor 42", Intent{Kind: IntentDelete, ExpenseID: 42}, true},
+		{"delete #8!", Intent{Kind: IntentDelete, ExpenseID: 8}, true},
+		{"hapus", Intent{Kind: IntentDelete, Missing: true}, true},
+		{"hapus kopi 25rb", Intent{}, false},
+
+		{"set budget 100rb", Intent{Kind: IntentBudget, Amount: 100000}, true},
+		{"anggaran harian jadi 1,5jt", Intent{Kind: IntentBudget, Amount: 1500000}, true},
+		{"atur anggaran harian 75.000", Intent{Kind: IntentBudget, Amount: 75000}, true},
+		{"daily budget 50 k", Intent{Kind: IntentBudget, Amount: 50000}, true},
+		{"anggaran harian off", Intent{Kind: IntentBudget}, true},
+		{"budget harian 0", Intent{Kind: IntentBudget}, true},
+		{"hapus anggaran harian", Intent{Kind: IntentBudget}, true},
+		{"set budget", Intent{Kind: IntentBudget, Missing: true}, true},
+		{"set budget seratus ribu", Intent{}, false},
+
+		{"berapa pengeluaran minggu ini", Intent{Kind: IntentQuestion}, true},
+		{"Kapan terakhir beli bensin", Intent{Kind: IntentQuestion}, true},
+		{"total makan bulan ini?", Intent{Kind: IntentQuestion}, true},
+
+		{"lihat pengeluaran", Intent{Kind: IntentList}, true},
+		{"tampilkan semua dong", Intent{Kind: IntentList}, true},
+		{"rekap bulan ini", Intent{Kind: IntentMonthly}, true},
+		{"ringkasan minggu ini", Intent{Kind: IntentWeekly}, true},
+		{"bantuan", Intent{Kind: IntentHelp}, true},
+		{"cara pakai bot?", Intent{Kind: IntentHelp}, true},
+		{"helper kabel 20rb", Intent{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := classifyByRules(tt.text)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("classifyByRules(%q) = %+v, %v, want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestClassifyIntentWithoutAI(t *testing.T) {
+	s, _ := newTestService(t)
+
+	// Command words the rules cannot place fall back to an expense when no AI is configured
+	for _, text := range []string{"kopi 25rb", "hotel budget 300rb", "ganti oli motor 80rb"} {
+		if got := s.ClassifyIntent(context.Background(), text); got.Kind != IntentExpense {
+			t.Errorf("ClassifyIntent(%q) = %+v, want an expense", text, got)
+		}
+	}
+	if got := s.ClassifyIntent(context.Background(), "hapus 5"); got.Kind != IntentDelete || got.ExpenseID != 5 {
+		t.Errorf("ClassifyIntent(hapus 5) = %+v, want delete 5", got)
+	}
+}
+
+func TestParseAmountWord(t *testing.T) {
+	tests := []struct {
+		word string
+		want float64
+		ok   bool
+	}{
+		{"25000", 25000, true},
+		{"75.000", 75000, true},
+		{"1.250.000", 1250000, true},
+		{"75,000", 75000, true},
+		{"Rp50.000", 50000, true},
+		{"rp.15000", 15000, true},
+		{"50k", 50000, true},
+		{"30rb", 30000, true},
+		{"30 ribu", 0, false},
+		{"30ribu", 30000, true},
+		{"2jt", 2000000, true},
+		{"1,5jt", 1500000, true},
+		{"1.5juta", 1500000, true},
+		{"2,5k", 2500, true},
+		{"0", 0, false},
+		{"-5000", 0, false},
+		{"rb", 0, false},
+		{"", 0, false},
+		{"seratus", 0, false},
+		{"12abc", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseAmountWord(tt.word)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseAmountWord(%q) = %v, %v, want %v, %v", tt.word, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestContainsWords(t *testing.T) {
+	tests := []struct {
+		text     string
+		keywords []string
+		want     bool
+	}{
+		{"need help", []string{"help"}, true},
+		{"helper cable", []string{"help"}, false},
+		{"coffee", []string{"off"}, false},
+		{"turn it OFF!", []string{"off"}, true},
+		{"Lihat-pengeluaran bulan ini", []string{"lihat pengeluaran"}, true},
+		{"", []string{"help"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := containsWords(tt.text, tt.keywords); got != tt.want {
+			t.Errorf("containsWords(%q, %v) = %v, want %v", tt.text, tt.keywords, got, tt.want)
+		}
+	}
+}
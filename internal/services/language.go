@@ -0,0 +1,41 @@
+package services
+
+import "SmartExpenseAI/internal/i18n"
+
+// Language returns the language the user reads the bot in, i18n.Default until it is known
+func (s *Service) Language(userID uint) (i18n.Lang, error) {
+	pref, err := s.repo.GetUserPreference(userID)
+	if err != nil {
+		return i18n.Default, err
+	}
+	if lang, ok := i18n.Parse(pref.Language); ok {
+		return lang, nil
+	}
+	return i18n.Default, nil
+}
+
+// DetectLanguage sets the user's language from the language code of their Telegram client,
+// unless the language was already set
+func (s *Service) DetectLanguage(userID uint, languageCode string) error {
+	pref, err := s.repo.GetUserPreference(userID)
+	if err != nil {
+		return err
+	}
+	if pref.Language != "" {
+		return nil
+	}
+
+	pref.Language = string(i18n.Detect(languageCode))
+	return s.repo.SaveUserPreference(pref)
+}
+
+// SetLanguage changes the language the user reads the bot in
+func (s *Service) SetLanguage(userID uint, lang i18n.Lang) error {
+	pref, err := s.repo.GetUserPreference(userID)
+	if err != nil {
+		return err
+	}
+
+	pref.Language = string(lang)
+	return s.repo.SaveUserPreference(pref)
+}
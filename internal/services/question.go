@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+// ErrQuestionNotUnderstood is returned when a question cannot be turned into a valid query plan
+var ErrQuestionNotUnderstood = errors.New("question not understood")
+
+// Aggregations and groupings a query plan may ask for
+const (
+	AggregateSum     = "sum"
+	AggregateCount   = "count"
+	AggregateAverage = "average"
+	AggregateMax     = "max"
+	AggregateMin     = "min"
+	AggregateLatest  = "latest"
+	AggregateList    = "list"
+
+	GroupByCategory = "category"
+	GroupByMonth    = "month"
+)
+
+// maxAnswerRows is how many matching expenses an answer shows at most
+const maxAnswerRows = 10
+
+// QueryPlan is the constrained query the AI derives from a question. It can only narrow down
+// the asking user's own expenses and pick one of the aggregations above; the code runs it.
+type QueryPlan struct {
+	// From and To are inclusive dates in YYYY-MM-DD format, empty for no bound
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Category string `json:"category"`
+	// Keywords match the description, merchant or category, any of them is enough
+	Keywords  []string `json:"keywords"`
+	MinAmount float64  `json:"min_amount"`
+	MaxAmount float64  `json:"max_amount"`
+	Aggregate string   `json:"aggregate"`
+	GroupBy   string   `json:"group_by"`
+	Limit     int      `json:"limit"`
+}
+
+// AnswerGroup is the total of one category or month
+type AnswerGroup struct {
+	Category string
+	Month    time.Time
+	Count    int
+	Total    float64
+}
+
+// Answer is the result of a query plan
+type Answer struct {
+	Plan  QueryPlan
+	From  *time.Time
+	To    *time.Time
+	Count int
+	Total float64
+	// Value is the result of average, max and min
+	Value  float64
+	Groups []AnswerGroup
+	// Rows are the matching expenses worth showing, newest first
+	Rows []models.Expense
+}
+
+// AnswerQuestion lets the AI plan a query for a question about the user's expenses and runs it
+func (s *Service) AnswerQuestion(ctx context.Context, userID uint, question string) (*Answer, error) {
+	if !s.ai.Configured() {
+		return nil, ErrQuestionNotUnderstood
+	}
+
+	plan, err := s.ai.PlanQuestion(ctx, question, time.Now().In(s.location))
+	if err != nil {
+		return nil, err
+	}
+	return s.RunQueryPlan(userID, plan)
+}
+
+// RunQueryPlan validates a query plan and runs it against the user's expenses
+func (s *Service) RunQueryPlan(userID uint, plan QueryPlan) (*Answer, error) {
+	plan, err := s.validatePlan(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := &Answer{Plan: plan}
+	filter := repository.ExpenseFilter{Category: plan.Category}
+	if plan.From != "" {
+		from, _ := time.ParseInLocation("2006-01-02", plan.From, s.location)
+		filter.From = &from
+		answer.From = &from
+	}
+	if plan.To != "" {
+		to, _ := time.ParseInLocation("2006-01-02", plan.To, s.location)
+		answer.To = &to
+		end := to.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+	if plan.MinAmount > 0 {
+		filter.MinAmount = &plan.MinAmount
+	}
+	if plan.MaxAmount > 0 {
+		filter.MaxAmount = &plan.MaxAmount
+	}
+
+	expenses, err := s.repo.GetExpensesFiltered(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []models.Expense
+	for _, expense := range expenses {
+		if matchesKeywords(expense, plan.Keywords) {
+			matched = append(matched, expense)
+		}
+	}
+
+	answer.Count = len(matched)
+	for _, expense := range matched {
+		answer.Total += expense.Amount
+	}
+	if len(matched) == 0 {
+		return answer, nil
+	}
+
+	switch plan.Aggregate {
+	case AggregateAverage:
+		answer.Value = answer.Total / float64(len(matched))
+	case AggregateMax, AggregateMin:
+		pick := 0
+		for i, expense := range matched {
+			if (plan.Aggregate == AggregateMax && expense.Amount > matched[pick].Amount) ||
+				(plan.Aggregate == AggregateMin && expense.Amount < matched[pick].Amount) {
+				pick = i
+			}
+		}
+		answer.Value = matched[pick].Amount
+		answer.Rows = []models.Expense{matched[pick]}
+	case AggregateLatest:
+		// Expenses come sorted newest first
+		answer.Rows = []models.Expense{matched[0]}
+	}
+
+	if answer.Rows == nil {
+		answer.Rows = matched
+		if len(answer.Rows) > plan.Limit {
+			answer.Rows = answer.Rows[:plan.Limit]
+		}
+	}
+
+	if plan.GroupBy != "" {
+		answer.Groups = s.groupExpenses(matched, plan.GroupBy)
+	}
+	return answer, nil
+}
+
+// parseQueryPlan decodes a query plan written by the AI. Fields the plan does not have are
+// rejected rather than ignored, so that a plan cannot ask for more than it is able to express.
+func parseQueryPlan(content string) (QueryPlan, error) {
+	var plan QueryPlan
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&plan); err != nil {
+		return plan, fmt.Errorf("%w: %v", ErrQuestionNotUnderstood, err)
+	}
+	return plan, nil
+}
+
+// validatePlan rejects plans outside the allowed shape and fills in defaults
+func (s *Service) validatePlan(plan QueryPlan) (QueryPlan, error) {
+	switch plan.Aggregate {
+	case "":
+		plan.Aggregate = AggregateList
+	case AggregateSum, AggregateCount, AggregateAverage, AggregateMax, AggregateMin, AggregateLatest, AggregateList:
+	default:
+		return plan, fmt.Errorf("%w: unknown aggregate %q", ErrQuestionNotUnderstood, plan.Aggregate)
+	}
+
+	switch plan.GroupBy {
+	case "", GroupByCategory, GroupByMonth:
+	default:
+		return plan, fmt.Errorf("%w: unknown grouping %q", ErrQuestionNotUnderstood, plan.GroupBy)
+	}
+
+	for _, date := range []string{plan.From, plan.To} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return plan, fmt.Errorf("%w: invalid date %q", ErrQuestionNotUnderstood, date)
+		}
+	}
+	if plan.From != "" && plan.To != "" && plan.From > plan.To {
+		plan.From, plan.To = plan.To, plan.From
+	}
+
+	if plan.MinAmount < 0 || plan.MaxAmount < 0 {
+		return plan, fmt.Errorf("%w: negative amount", ErrQuestionNotUnderstood)
+	}
+
+	plan.Category = truncate(strings.TrimSpace(plan.Category), 50)
+	var keywords []string
+	for _, keyword := range plan.Keywords {
+		keyword = truncate(strings.ToLower(strings.TrimSpace(keyword)), 50)
+		if keyword != "" && len(keywords) < 5 {
+			keywords = append(keywords, keyword)
+		}
+	}
+	plan.Keywords = keywords
+
+	if plan.Limit <= 0 || plan.Limit > maxAnswerRows {
+		plan.Limit = maxAnswerRows
+	}
+	return plan, nil
+}
+
+// groupExpenses totals expenses per category, largest first, or per month, newest first
+func (s *Service) groupExpenses(expenses []models.Expense, groupBy string) []AnswerGroup {
+	index := make(map[string]int)
+	var groups []AnswerGroup
+	for _, expense := range expenses {
+		group := AnswerGroup{Category: expense.Category}
+		key := expense.Category
+		if groupBy == GroupByMonth {
+			date := expense.Date.In(s.location)
+			group = AnswerGroup{Month: time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, s.location)}
+			key = date.Format("2006-01")
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, group)
+		}
+		groups[i].Count++
+		groups[i].Total += expense.Amount
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groupBy == GroupByMonth {
+			return groups[i].Month.After(groups[j].Month)
+		}
+		return groups[i].Total > groups[j].Total
+	})
+	return groups
+}
+
+// matchesKeywords reports whether the description, merchant or category contains any keyword
+func matchesKeywords(expense models.Expense, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	text := strings.ToLower(expense.Description + " " + expense.Merchant + " " + expense.Category)
+	return containsAny(text, keywords)
+}
+
+// containsAny checks if the text contains any of the keywords
+func containsAny(text string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(text string, length int) string {
+	if len([]rune(text)) <= length {
+		return text
+	}
+	return string([]rune(text)[:length])
+}
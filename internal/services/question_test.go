@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseQueryPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"full plan", `{"from":"2026-10-01","to":"2026-10-31","category":"Makanan","keywords":["kopi"],"min_amount":0,"max_amount":0,"aggregate":"sum","group_by":"","limit":10}`, false},
+		{"partial plan", `{"aggregate":"latest","keywords":["bensin"]}`, false},
+		{"unknown field", `{"aggregate":"sum","user_id":7}`, true},
+		{"raw query", `{"aggregate":"sum","sql":"SELECT * FROM expenses"}`, true},
+		{"wrong type", `{"aggregate":"sum","limit":"all"}`, true},
+		{"not JSON", `total is Rp50.000`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseQueryPlan(tt.content)
+			if tt.wantErr && !errors.Is(err, ErrQuestionNotUnderstood) {
+				t.Errorf("err = %v, want ErrQuestionNotUnderstood", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("err = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidatePlan(t *testing.T) {
+	s, _ := newTestService(t)
+
+	tests := []struct {
+		name    string
+		plan    QueryPlan
+		want    QueryPlan
+		wantErr bool
+	}{
+		{"defaults", QueryPlan{}, QueryPlan{Aggregate: AggregateList, Limit: maxAnswerRows}, false},
+		{"unknown aggregate", QueryPlan{Aggregate: "median"}, QueryPlan{}, true},
+		{"raw aggregate", QueryPlan{Aggregate: "SUM(amount)"}, QueryPlan{}, true},
+		{"unknown grouping", QueryPlan{GroupBy: "week"}, QueryPlan{}, true},
+		{"relative period", QueryPlan{From: "last week"}, QueryPlan{}, true},
+		{"impossible date", QueryPlan{To: "2026-13-01"}, QueryPlan{}, true},
+		{"date with time", QueryPlan{From: "2026-10-01T00:00:00Z"}, QueryPlan{}, true},
+		{"negative amount", QueryPlan{MinAmount: -1}, QueryPlan{}, true},
+		{"swapped period", QueryPlan{From: "2026-10-31", To: "2026-10-01", Aggregate: AggregateSum},
+			QueryPlan{From: "2026-10-01", To: "2026-10-31", Aggregate: AggregateSum, Limit: maxAnswerRows}, false},
+		{"limit above maximum", QueryPlan{Aggregate: AggregateList, Limit: 500}, QueryPlan{Aggregate: AggregateList, Limit: maxAnswerRows}, false},
+		{"keywords cleaned", QueryPlan{Aggregate: AggregateCount, Keywords: []string{" Kopi ", "", "SUSU", "a", "b", "c", "d"}, Limit: 3},
+			QueryPlan{Aggregate: AggregateCount, Keywords: []string{"kopi", "susu", "a", "b", "c"}, Limit: 3}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.validatePlan(tt.plan)
+			if tt.wantErr {
+				if !errors.Is(err, ErrQuestionNotUnderstood) {
+					t.Errorf("err = %v, want ErrQuestionNotUnderstood", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validatePlan: %v", err)
+			}
+			if got.From != tt.want.From || got.To != tt.want.To || got.Aggregate != tt.want.Aggregate ||
+				got.Limit != tt.want.Limit || len(got.Keywords) != len(tt.want.Keywords) {
+				t.Fatalf("validatePlan = %+v, want %+v", got, tt.want)
+			}
+			for i := range got.Keywords {
+				if got.Keywords[i] != tt.want.Keywords[i] {
+					t.Errorf("keyword %d = %q, want %q", i, got.Keywords[i], tt.want.Keywords[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRunQueryPlanPeriodInLocation(t *testing.T) {
+	s, _ := newTestService(t)
+	// Both ends of October in WIB fall on another day in UTC
+	addExpense(t, s, 1, "Kopi pagi", "Makanan", 20000, time.Date(2026, 10, 1, 0, 30, 0, 0, testLocation))
+	addExpense(t, s, 1, "Kopi malam", "Makanan", 30000, time.Date(2026, 10, 31, 23, 30, 0, 0, testLocation))
+	addExpense(t, s, 1, "Kopi September", "Makanan", 1000, time.Date(2026, 9, 30, 23, 30, 0, 0, testLocation))
+	addExpense(t, s, 1, "Kopi November", "Makanan", 2000, time.Date(2026, 11, 1, 0, 30, 0, 0, testLocation))
+	addExpense(t, s, 2, "Kopi orang lain", "Makanan", 4000, time.Date(2026, 10, 15, 12, 0, 0, 0, testLocation))
+
+	answer, err := s.RunQueryPlan(1, QueryPlan{From: "2026-10-01", To: "2026-10-31", Keywords: []string{"kopi"}, Aggregate: AggregateSum})
+	if err != nil {
+		t.Fatalf("RunQueryPlan: %v", err)
+	}
+	if answer.Count != 2 || answer.Total != 50000 {
+		t.Errorf("count, total = %d, %v, want 2, 50000", answer.Count, answer.Total)
+	}
+	if want := time.Date(2026, 10, 1, 0, 0, 0, 0, testLocation); answer.From == nil || !answer.From.Equal(want) {
+		t.Errorf("from = %v, want %v", answer.From, want)
+	}
+	if want := time.Date(2026, 10, 31, 0, 0, 0, 0, testLocation); answer.To == nil || !answer.To.Equal(want) {
+		t.Errorf("to = %v, want %v", answer.To, want)
+	}
+
+	latest, err := s.RunQueryPlan(1, QueryPlan{Keywords: []string{"kopi"}, Aggregate: AggregateLatest})
+	if err != nil {
+		t.Fatalf("RunQueryPlan latest: %v", err)
+	}
+	if len(latest.Rows) != 1 || latest.Rows[0].Description != "Kopi November" {
+		t.Errorf("latest rows = %v, want Kopi November", latest.Rows)
+	}
+
+	if _, err := s.RunQueryPlan(1, QueryPlan{Aggregate: "drop"}); !errors.Is(err, ErrQuestionNotUnderstood) {
+		t.Errorf("unknown aggregate: err = %v, want ErrQuestionNotUnderstood", err)
+	}
+
+	grouped, err := s.RunQueryPlan(1, QueryPlan{Aggregate: AggregateSum, GroupBy: GroupByMonth})
+	if err != nil {
+		t.Fatalf("RunQueryPlan by month: %v", err)
+	}
+	if len(grouped.Groups) != 3 || grouped.Groups[0].Month.Month() != time.November || grouped.Groups[1].Total != 50000 {
+		t.Errorf("groups = %+v, want November, October (50000) and September", grouped.Groups)
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeeklyRecap(t *testing.T) {
+	s, _ := newTestService(t)
+	now := time.Now()
+	addExpense(t, s, 1, "kopi", "Food", 25000, now.Add(-time.Hour))
+	addExpense(t, s, 1, "nasi goreng", "Food", 15000, now.AddDate(0, 0, -3))
+	addExpense(t, s, 1, "ojek", "Transport", 20000, now.AddDate(0, 0, -6))
+	// Outside the last 7 days, and of another user
+	addExpense(t, s, 1, "bensin", "Transport", 50000, now.AddDate(0, 0, -8))
+	addExpense(t, s, 2, "kopi", "Food", 99000, now)
+
+	recap, err := s.WeeklyRecap(1)
+	if err != nil {
+		t.Fatalf("WeeklyRecap: %v", err)
+	}
+	if recap.Count != 3 || recap.Total != 60000 {
+		t.Errorf("WeeklyRecap count, total = %d, %v, want 3, 60000", recap.Count, recap.Total)
+	}
+
+	totals := make(map[string]float64)
+	for _, category := range recap.Categories {
+		totals[category.Category] = category.Total
+	}
+	if totals["Food"] != 40000 || totals["Transport"] != 20000 || len(totals) != 2 {
+		t.Errorf("WeeklyRecap categories = %v, want Food 40000 and Transport 20000", totals)
+	}
+}
+
+func TestMonthlyRecapGroupsByMonthNewestFirst(t *testing.T) {
+	s, _ := newTestService(t)
+	now := time.Now()
+	dates := []time.Time{now.Add(-time.Hour), now.AddDate(0, 0, -10), now.AddDate(0, 0, -20), now.AddDate(0, 0, -29)}
+	for i, date := range dates {
+		addExpense(t, s, 1, "belanja", "Food", float64(1000*(i+1)), date)
+	}
+	addExpense(t, s, 1, "lama", "Food", 50000, now.AddDate(0, 0, -31))
+
+	recap, err := s.MonthlyRecap(1)
+	if err != nil {
+		t.Fatalf("MonthlyRecap: %v", err)
+	}
+	if recap.Total != 10000 {
+		t.Errorf("MonthlyRecap total = %v, want 10000", recap.Total)
+	}
+
+	count := 0
+	for i, month := range recap.Months {
+		if i > 0 && !month.Month.Before(recap.Months[i-1].Month) {
+			t.Errorf("months are not newest first: %v after %v", month.Month, recap.Months[i-1].Month)
+		}
+		var total float64
+		for _, expense := range month.Expenses {
+			if expense.Date.Month() != month.Month.Month() || expense.Date.Year() != month.Month.Year() {
+				t.Errorf("expense of %v listed under %v", expense.Date, month.Month)
+			}
+			total += expense.Amount
+		}
+		if total != month.Total {
+			t.Errorf("month %v total = %v, want the sum %v", month.Month, month.Total, total)
+		}
+		count += len(month.Expenses)
+	}
+	if count != len(dates) {
+		t.Errorf("MonthlyRecap lists %d expenses, want %d", count, len(dates))
+	}
+}
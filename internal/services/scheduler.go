@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/robfig/cron/v3"
+
+	"SmartExpenseAI/internal/repository"
+)
+
+// jobLockTTL bounds how long a crashed instance can keep a job locked
+const jobLockTTL = 10 * time.Minute
+
+// Scheduler runs cron jobs persisted in the repository so that every slot runs
+// at most once across all instances, and missed slots are caught up on start
+type Scheduler struct {
+	repo       repository.Jobs
+	location   *time.Location
+	instanceID string
+
+	mu      sync.Mutex
+	cron    *gocron.Scheduler
+	jobs    map[string]*registeredJob
+	stopped bool
+	running sync.WaitGroup
+}
+
+// registeredJob is a job known to this instance, persisted in the scheduled_jobs table
+type registeredJob struct {
+	cronExpr string
+	schedule cron.Schedule
+	// run is given the scheduled time of the slot it runs for
+	run func(slot time.Time)
+}
+
+// JobStatus describes the persisted state of a scheduled job
+type JobStatus struct {
+	Name      string     `json:"name"`
+	Schedule  string     `json:"schedule"`
+	LastRunAt *time.Time `json:"last_run_at"`
+	NextRunAt *time.Time `json:"next_run_at"`
+	RunCount  int        `json:"run_count"`
+	LastError string     `json:"last_error,omitempty"`
+	Running   bool       `json:"running"`
+	LockedBy  string     `json:"locked_by,omitempty"`
+}
+
+func NewScheduler(repo repository.Jobs, loc *time.Location, instanceID string) *Scheduler {
+	return &Scheduler{
+		repo:       repo,
+		location:   loc,
+		instanceID: instanceID,
+		cron:       gocron.NewScheduler(loc),
+		jobs:       make(map[string]*registeredJob),
+	}
+}
+
+// InstanceID returns the identifier this process uses for job locks
+func (s *Scheduler) InstanceID() string {
+	return s.instanceID
+}
+
+// Start runs the scheduled jobs in the background, then catches up on runs
+// that were missed while the instance was down
+func (s *Scheduler) Start() {
+	s.cron.StartAsync()
+
+	go s.catchUpMissedRuns()
+}
+
+// Schedule registers (or replaces) a persisted job
+func (s *Scheduler) Schedule(name string, cronExpr string, run func()) error {
+	return s.ScheduleSlots(name, cronExpr, func(time.Time) { run() })
+}
+
+// ScheduleSlots registers (or replaces) a persisted job that needs to know which slot it
+// runs for, such as a daily report; a run caught up on start gets the slot that was missed
+func (s *Scheduler) ScheduleSlots(name string, cronExpr string, run func(slot time.Time)) error {
+	schedule, err := cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", s.location.String(), cronExpr))
+	if err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", cronExpr, err)
+	}
+
+	if _, err := s.repo.RegisterScheduledJob(name, cronExpr); err != nil {
+		return fmt.Errorf("failed to persist job: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cron.RemoveByTag(name)
+	s.jobs[name] = &registeredJob{cronExpr: cronExpr, schedule: schedule, run: run}
+
+	_, err = s.cron.Cron(cronExpr).Tag(name).Do(func() {
+		s.runJob(name, time.Now().In(s.location).Truncate(time.Minute))
+	})
+	return err
+}
+
+// Unschedule removes a job from the scheduler and from the repository
+func (s *Scheduler) Unschedule(name string) {
+	s.mu.Lock()
+	s.cron.RemoveByTag(name)
+	delete(s.jobs, name)
+	s.mu.Unlock()
+
+	if err := s.repo.DeleteScheduledJob(name); err != nil {
+		log.Printf("Error deleting job %s: %v", name, err)
+	}
+}
+
+// Statuses returns the persisted state of every scheduled job
+func (s *Scheduler) Statuses() ([]JobStatus, error) {
+	rows, err := s.repo.GetScheduledJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]JobStatus, 0, len(rows))
+	for _, row := range rows {
+		status := JobStatus{
+			Name:      row.Name,
+			Schedule:  row.Schedule,
+			LastRunAt: row.LastRunAt,
+			RunCount:  row.RunCount,
+			LastError: row.LastError,
+			Running:   row.LockedUntil != nil && row.LockedUntil.After(now),
+		}
+		if status.Running {
+			status.LockedBy = row.LockedBy
+		}
+		if job, ok := s.jobs[row.Name]; ok {
+			next := job.schedule.Next(now)
+			status.NextRunAt = &next
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Stop stops starting jobs and waits until the running ones finish or ctx is done
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.cron.Stop()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runJob executes a job for a slot, at most once per scheduled slot across all instances
+func (s *Scheduler) runJob(name string, slot time.Time) {
+	s.mu.Lock()
+	job, ok := s.jobs[name]
+	if s.stopped {
+		ok = false
+	} else if ok {
+		s.running.Add(1)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	defer s.running.Done()
+
+	now := time.Now()
+	acquired, err := s.repo.AcquireJobLock(name, s.instanceID, now.Add(jobLockTTL))
+	if err != nil {
+		log.Printf("Error locking job %s: %v", name, err)
+		return
+	}
+	if !acquired {
+		log.Printf("Job %s is already running on another instance", name)
+		return
+	}
+
+	// Skip if another instance already ran this slot
+	row, err := s.repo.GetScheduledJob(name)
+	if err != nil {
+		log.Printf("Error loading job %s: %v", name, err)
+		s.repo.ReleaseJobLock(name, s.instanceID)
+		return
+	}
+	if row.LastRunAt != nil && job.schedule.Next(*row.LastRunAt).After(now) {
+		s.repo.ReleaseJobLock(name, s.instanceID)
+		return
+	}
+
+	lastError := ""
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				lastError = fmt.Sprintf("panic: %v", r)
+				log.Printf("Job %s panicked: %v", name, r)
+			}
+		}()
+		job.run(slot)
+	}()
+
+	if err := s.repo.FinishJobRun(name, s.instanceID, now, lastError); err != nil {
+		log.Printf("Error recording run of job %s: %v", name, err)
+	}
+}
+
+// catchUpMissedRuns runs every job whose scheduled time passed while no instance was running
+func (s *Scheduler) catchUpMissedRuns() {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+
+	now := time.Now()
+	for _, name := range names {
+		row, err := s.repo.GetScheduledJob(name)
+		if err != nil {
+			log.Printf("Error loading job %s: %v", name, err)
+			continue
+		}
+
+		s.mu.Lock()
+		job, ok := s.jobs[name]
+		s.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		// Jobs that never ran are measured from when they were registered
+		reference := row.CreatedAt
+		if row.LastRunAt != nil {
+			reference = *row.LastRunAt
+		}
+
+		missed := job.schedule.Next(reference)
+		if !missed.Before(now) {
+			continue
+		}
+		// Only the latest missed slot is run, so a long outage does not replay every day
+		for next := job.schedule.Next(missed); next.Before(now); next = job.schedule.Next(next) {
+			missed = next
+		}
+		log.Printf("Catching up job %s missed at %s", name, missed.Format(time.RFC3339))
+		s.runJob(name, missed)
+	}
+}
+
+// DailyCron converts a "HH:MM" time of day into a cron expression
+func DailyCron(clock string) (string, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return "", fmt.Errorf("time must be in HH:MM format: %w", err)
+	}
+	return fmt.Sprintf("%d %d * * *", t.Minute(), t.Hour()), nil
+}
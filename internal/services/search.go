@@ -0,0 +1,38 @@
+package services
+
+import (
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository"
+)
+
+// searchPageSize is how many expenses one page of search results shows
+const searchPageSize = 10
+
+// SearchResult is one page of expenses matching a search
+type SearchResult struct {
+	Expenses []models.Expense
+	Total    int64
+	// Page starts at 1
+	Page  int
+	Pages int
+}
+
+// SearchExpenses returns one page of the user's expenses matching the filter, newest first.
+// Filtering and paging happen in the database.
+func (s *Service) SearchExpenses(userID uint, filter repository.ExpenseFilter, page int) (*SearchResult, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	expenses, total, err := s.repo.ListExpensesPage(userID, filter, "date", true, searchPageSize, (page-1)*searchPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResult{
+		Expenses: expenses,
+		Total:    total,
+		Page:     page,
+		Pages:    int((total + searchPageSize - 1) / searchPageSize),
+	}, nil
+}
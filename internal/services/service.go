@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"SmartExpenseAI/internal/config"
+	"SmartExpenseAI/internal/repository"
+)
+
+// ErrExpired is returned when a pending confirmation is unknown or no longer answerable
+var ErrExpired = errors.New("confirmation expired")
+
+// Service implements the expense tracking use cases on top of a repository.
+// It returns data structures and never talks to Telegram; presenting results is up to the caller.
+type Service struct {
+	repo              repository.Repository
+	ai                AI
+	location          *time.Location
+	publicURL         string
+	maxImportFileSize int64
+}
+
+func New(repo repository.Repository, ai AI, cfg *config.Config) *Service {
+	return &Service{
+		repo:              repo,
+		ai:                ai,
+		location:          cfg.Location,
+		publicURL:         cfg.Server.PublicURL,
+		maxImportFileSize: cfg.Import.MaxFileSize,
+	}
+}
+
+// Location is the timezone used for "today" boundaries and displayed times
+func (s *Service) Location() *time.Location {
+	return s.location
+}
+
+// Ping checks that the repository is reachable
+func (s *Service) Ping(ctx context.Context) error {
+	return s.repo.Ping(ctx)
+}
+
+// AIConfigured reports whether the AI provider can be called
+func (s *Service) AIConfigured() bool {
+	return s.ai.Configured()
+}
+
+// startOfDay returns midnight of the day of t in the service timezone
+func (s *Service) startOfDay(t time.Time) time.Time {
+	local := t.In(s.location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.location)
+}
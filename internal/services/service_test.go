@@ -0,0 +1,33 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"SmartExpenseAI/internal/config"
+	"SmartExpenseAI/internal/models"
+	"SmartExpenseAI/internal/repository/memory"
+)
+
+// testLocation stands in for Asia/Jakarta without depending on the tz database
+var testLocation = time.FixedZone("WIB", 7*60*60)
+
+// newTestService returns a service on an empty in-memory repository, without an AI provider
+func newTestService(t *testing.T) (*Service, *memory.Store) {
+	t.Helper()
+
+	repo := memory.New()
+	cfg := &config.Config{Location: testLocation}
+	return New(repo, NewOpenRouter(config.AIConfig{}), cfg), repo
+}
+
+// addExpense stores an expense through the service, failing the test on error
+func addExpense(t *testing.T, s *Service, userID uint, description string, category string, amount float64, date time.Time) *models.Expense {
+	t.Helper()
+
+	expense := &models.Expense{UserID: userID, Description: description, Category: category, Amount: amount, Date: date}
+	if err := s.CreateExpense(expense); err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	return expense
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"SmartExpenseAI/internal/models"
+)
+
+// apiTokenPrefix makes tokens recognizable when they leak into logs or repositories
+const apiTokenPrefix = "sei_"
+
+// HashAPIToken returns the value stored in the database for a raw token
+func HashAPIToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuthenticateAPIToken resolves a raw bearer token to its stored token
+func (s *Service) AuthenticateAPIToken(raw string) (*models.APIToken, error) {
+	token, err := s.repo.GetAPITokenByHash(HashAPIToken(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.TouchAPIToken(token.ID, time.Now()); err != nil {
+		log.Printf("Error updating API token usage: %v", err)
+	}
+	return token, nil
+}
+
+// GenerateAPIToken creates a new API token for the user and returns the raw token,
+// which is not stored and cannot be shown again
+func (s *Service) GenerateAPIToken(userID uint) (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := apiTokenPrefix + hex.EncodeToString(b)
+
+	token := models.APIToken{
+		UserID:    userID,
+		Prefix:    raw[:len(apiTokenPrefix)+6],
+		TokenHash: HashAPIToken(raw),
+	}
+	if err := s.repo.CreateAPIToken(&token); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// ListAPITokens returns the user's active API tokens
+func (s *Service) ListAPITokens(userID uint) ([]models.APIToken, error) {
+	return s.repo.GetAPITokensByUserID(userID)
+}
+
+// RevokeAPITokens deletes all API tokens of the user and returns how many there were
+func (s *Service) RevokeAPITokens(userID uint) (int64, error) {
+	return s.repo.DeleteAPITokens(userID)
+}
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	_ "time/tzdata"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	fiber "github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
 
+	"SmartExpenseAI/internal/config"
 	"SmartExpenseAI/internal/database"
+	"SmartExpenseAI/internal/dispatcher"
+	"SmartExpenseAI/internal/presenter"
 	"SmartExpenseAI/internal/routes"
 	"SmartExpenseAI/internal/services"
 )
@@ -20,56 +27,110 @@ func main() {
 		log.Println("No .env file found")
 	}
 
-	// Initialize database connection
-	database.InitDB()
+	// "server migrate ..." manages the schema instead of starting the bot
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	// Load and validate all settings once
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// Get bot token from environment
-	botToken := os.Getenv("TELEGRAM_BOT_TOKEN")
-	if botToken == "" {
-		log.Fatal("TELEGRAM_BOT_TOKEN environment variable is not set")
+	// Initialize database connection
+	store, err := database.Open(cfg.Database)
+	if err != nil {
+		log.Fatal("Failed to connect to database: ", err)
 	}
 
 	// Initialize bot for scheduler
-	bot, err := tgbotapi.NewBotAPI(botToken)
+	bot, err := tgbotapi.NewBotAPI(cfg.Telegram.BotToken)
 	if err != nil {
 		log.Fatal("Failed to create new bot: ", err)
 	}
 
-	// Get user ID from environment for the scheduled recap
-	allowedUserIDStr := os.Getenv("TELEGRAM_USER_ID")
-	if allowedUserIDStr == "" {
-		log.Fatal("TELEGRAM_USER_ID environment variable is not set")
+	// Wire storage, domain logic and Telegram presentation together
+	service := services.New(store, services.NewOpenRouter(cfg.AI), cfg)
+	scheduler := services.NewScheduler(store, cfg.Location, cfg.Server.InstanceID)
+	handlers := routes.New(bot, cfg, service, scheduler, presenter.NewTelegram(bot, cfg.Location))
+
+	// Create the Fiber app serving the webhook, health checks, job status, REST API and dashboard
+	app := fiber.New()
+
+	// Process updates on a bounded worker pool instead of one goroutine per update
+	updateDispatcher := dispatcher.New(
+		cfg.Workers.Count,
+		cfg.Workers.QueueSize,
+		cfg.Workers.UpdateTimeout,
+		handlers.ProcessUpdate,
+	)
+	handlers.SetDispatcher(updateDispatcher)
+
+	// Show the registered commands in the command menu of Telegram clients
+	if err := handlers.SyncCommands(); err != nil {
+		log.Printf("Failed to sync bot commands: %v", err)
 	}
 
-	allowedUserID, err := strconv.ParseInt(allowedUserIDStr, 10, 64)
-	if err != nil {
-		log.Fatal("TELEGRAM_USER_ID must be a valid integer: ", err)
+	// Receive updates through the webhook (default) or by long polling
+	if cfg.Telegram.Mode == "polling" {
+		go handlers.StartPolling()
+	} else {
+		handlers.TelegramRoutes(app)
 	}
 
-	// Create Fiber app - only for webhook handling
-	app := fiber.New()
+	// Register health and readiness checks
+	handlers.HealthRoutes(app)
+
+	// Register scheduled job status route
+	handlers.JobRoutes(app)
 
-	// Initialize Telegram bot and user ID
-	routes.InitTelegram(bot, allowedUserID)
+	// Register the token-authenticated REST API
+	handlers.APIRoutes(app)
 
-	// Register Telegram routes
-	routes.TelegramRoutes(app)
+	// Register the web dashboard
+	handlers.DashboardRoutes(app)
 
-	// Schedule weekly recap
-	go services.ScheduleWeeklyRecap(bot, allowedUserID)
+	// Start the scheduler (weekly recap, daily digests and reminders)
+	handlers.ScheduleJobs()
+	scheduler.Start()
 
-	// Get port from environment variable or default to 8080
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Start the server
+	go func() {
+		log.Printf("Server starting on port %d", cfg.Server.Port)
+		if err := app.Listen(":" + strconv.Itoa(cfg.Server.Port)); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	// Wait for Render (or Ctrl+C) to stop the instance
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+
+	log.Println("Shutting down")
+	handlers.SetShuttingDown()
+	if cfg.Telegram.Mode == "polling" {
+		handlers.StopPolling()
 	}
 
-	portNum, err := strconv.Atoi(port)
-	if err != nil {
-		portNum = 8080
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting requests first, then drain queued updates and running jobs
+	if err := app.ShutdownWithContext(ctx); err != nil {
+		log.Printf("HTTP server did not shut down cleanly: %v", err)
+	}
+	if err := updateDispatcher.Shutdown(ctx); err != nil {
+		log.Printf("Update dispatcher did not drain: %v", err)
+	}
+	if err := scheduler.Stop(ctx); err != nil {
+		log.Printf("Scheduled jobs did not finish: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
 	}
 
-	// Start the server
-	log.Printf("Server starting on port %d", portNum)
-	log.Fatal(app.Listen(":" + strconv.Itoa(portNum)))
+	log.Println("Shutdown complete")
 }
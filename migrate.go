@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"SmartExpenseAI/internal/config"
+	"SmartExpenseAI/internal/database"
+)
+
+const migrateUsage = `Usage: server migrate [command]
+
+Commands:
+  up          apply all pending migrations (default)
+  down [N]    roll back the last N applied migrations (default 1); the initial
+              schema 0001 may have adopted existing tables and is never rolled back
+  status      list migrations and whether they are applied`
+
+// runMigrate implements the "migrate" subcommand
+func runMigrate(args []string) {
+	cfg, err := config.LoadDatabase()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Migrations are run explicitly here, never implicitly on open
+	cfg.MigrateOnStart = false
+	store, err := database.Open(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer store.Close()
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := store.Migrate()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				log.Fatal("down expects a positive number of migrations")
+			}
+		}
+		if _, err := store.Rollback(steps); err != nil {
+			log.Fatal(err)
+		}
+
+	case "status":
+		statuses, err := store.MigrationStatus()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+}